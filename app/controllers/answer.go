@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/app/queries"
+	"github.com/memnix/memnixrest/pkg/database"
+	"github.com/memnix/memnixrest/pkg/utils"
+	"net/http"
+	"strconv"
+)
+
+// GetAnswersByCard method
+// @Description Get the alternative answers accepted for a card
+// @Summary gets a list of answers
+// @Tags Answer
+// @Produce json
+// @Param id path int true "card id"
+// @Security Beaver
+// @Success 200 {array} models.Answer
+// @Router /v1/cards/{cardID}/answers [get]
+func GetAnswersByCard(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+
+	// Params
+	id := c.Params("id")
+	cardID, _ := strconv.ParseUint(id, 10, 32)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	card := new(models.Card)
+
+	if err := db.First(&card, id).Error; err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on GetAnswersByCard: %s", auth.User.Email, err.Error()), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, uint(cardID))
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusServiceUnavailable, err.Error())
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, card.DeckID, models.AccessStudent); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - GetAnswersByCard: %s", auth.User.Email, card.DeckID, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	answers := queries.FetchAnswers(card.ID)
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success get answers by card.",
+		Data:    answers,
+		Count:   len(answers),
+	})
+}
+
+// CreateAnswer method
+// @Description Create a new alternative answer for a card
+// @Summary creates an answer
+// @Tags Answer
+// @Produce json
+// @Accept json
+// @Param answer body models.Answer true "Answer to create"
+// @Security Beaver
+// @Success 200
+// @Router /v1/answers/new [post]
+func CreateAnswer(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	answer := new(models.Answer)
+
+	if err := c.BodyParser(&answer); err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on CreateAnswer: %s", auth.User.Email, err.Error()), models.LogBodyParserError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, err.Error())
+	}
+
+	card := new(models.Card)
+
+	if err := db.First(&card, answer.CardID).Error; err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on CreateAnswer: %s", auth.User.Email, err.Error()), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, answer.CardID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusServiceUnavailable, err.Error())
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, card.DeckID, models.AccessEditor); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - CreateAnswer: %s", auth.User.Email, card.DeckID, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	if answer.NotValidate() {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on CreateAnswer: BadRequest", auth.User.Email), models.LogBadRequest).SetType(models.LogTypeError).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorAnswerLen)
+	}
+
+	db.Create(answer)
+
+	log := models.CreateLog(fmt.Sprintf("Created answer: %d - %s", answer.ID, answer.Answer), models.LogCardEdited).SetType(models.LogTypeInfo).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+	_ = log.SendLog()
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success register an answer",
+		Data:    *answer,
+		Count:   1,
+	})
+}
+
+// PUT
+
+// UpdateAnswerByID method
+// @Description Edit an alternative answer
+// @Summary edits an answer
+// @Tags Answer
+// @Produce json
+// @Success 200
+// @Accept json
+// @Param answer body models.Answer true "Answer to edit"
+// @Param answerID path string true "Answer ID"
+// @Security Beaver
+// @Router /v1/answers/{answerID}/edit [put]
+func UpdateAnswerByID(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+
+	// Params
+	id := c.Params("id")
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	answer := new(models.Answer)
+
+	if err := db.Joins("Card").First(&answer, id).Error; err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error on UpdateAnswerByID: %s from %s", err.Error(), auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, err.Error())
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, answer.Card.DeckID, models.AccessEditor); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - UpdateAnswerByID: %s", auth.User.Email, answer.Card.DeckID, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, answer.Card.DeckID, answer.CardID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	if err := UpdateAnswer(c, answer); !err.Success {
+		log := models.CreateLog(fmt.Sprintf("Error on UpdateAnswerByID: %s from %s", err.Message, auth.User.Email), models.LogBadRequest).SetType(models.LogTypeError).AttachIDs(auth.User.ID, answer.Card.DeckID, answer.CardID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, err.Message)
+	}
+
+	log := models.CreateLog(fmt.Sprintf("Edited answer: %d - %s", answer.ID, answer.Answer), models.LogCardEdited).SetType(models.LogTypeInfo).AttachIDs(auth.User.ID, answer.Card.DeckID, answer.CardID)
+	_ = log.SendLog()
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success update answer by ID",
+		Data:    *answer,
+		Count:   1,
+	})
+}
+
+// UpdateAnswer function
+func UpdateAnswer(c *fiber.Ctx, answer *models.Answer) *models.ResponseHTTP {
+	db := database.DBConn
+
+	res := new(models.ResponseHTTP)
+
+	// Only the answer text can be edited, the record and its card are kept
+	body := new(models.Answer)
+	if err := c.BodyParser(&body); err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	if body.CardID != 0 && body.CardID != answer.CardID {
+		res.GenerateError(utils.ErrorBreak)
+		return res
+	}
+
+	answer.Answer = body.Answer
+
+	if answer.NotValidate() {
+		res.GenerateError(utils.ErrorAnswerLen)
+		return res
+	}
+
+	db.Omit("Card").Save(answer)
+
+	res.GenerateSuccess("Success update answer", nil, 0)
+	return res
+}
+
+// DeleteAnswerByID method
+// @Description Delete an alternative answer
+// @Summary deletes an answer
+// @Tags Answer
+// @Produce json
+// @Success 200
+// @Param answerID path string true "Answer ID"
+// @Security Beaver
+// @Router /v1/answers/{answerID} [delete]
+func DeleteAnswerByID(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+	id := c.Params("id")
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	answer := new(models.Answer)
+
+	if err := db.Joins("Card").First(&answer, id).Error; err != nil {
+		return queries.RequestError(c, http.StatusServiceUnavailable, err.Error())
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, answer.Card.DeckID, models.AccessEditor); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d and answer %d - DeleteAnswerByID: %s", auth.User.Email, answer.Card.DeckID, answer.ID, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, answer.Card.DeckID, answer.CardID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	db.Delete(answer)
+
+	log := models.CreateLog(fmt.Sprintf("Deleted answer: %d - %s", answer.ID, answer.Answer), models.LogCardEdited).SetType(models.LogTypeInfo).AttachIDs(auth.User.ID, answer.Card.DeckID, answer.CardID)
+	_ = log.SendLog()
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success delete answer by ID",
+		Data:    *answer,
+		Count:   1,
+	})
+}
@@ -240,6 +240,7 @@ func CreateNewCard(c *fiber.Ctx) error {
 	}
 
 	db.Create(card)
+	card.UpdateLinkedMcq()
 
 	log := models.CreateLog(fmt.Sprintf("Created: %d - %s", card.ID, card.Question), models.LogCardCreated).SetType(models.LogTypeInfo).AttachIDs(auth.User.ID, card.DeckID, card.ID)
 	_ = log.SendLog()
@@ -303,7 +304,10 @@ func PostSelfEvaluateResponse(c *fiber.Ctx) error {
 	}
 
 	//TODO: Add error handling
-	_ = queries.PostSelfEvaluatedMem(&auth.User, card, response.Quality, response.Training)
+	if result := queries.PostSelfEvaluatedMem(&auth.User, card, response.Cloze, response.Quality, response.Training); result.Success {
+		memDate := result.Data.(models.MemDate)
+		queries.AdvanceSession(&auth.User, &memDate, response.Training, response.IsSuccess())
+	}
 
 	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
 		Success: true,
@@ -340,6 +344,12 @@ func PostResponse(c *fiber.Ctx) error {
 		return queries.RequestError(c, http.StatusBadRequest, err.Error())
 	}
 
+	if response.NotValidate() {
+		log := models.CreateLog(fmt.Sprintf("Error on PostResponse: BadRequest from %s", auth.User.Email), models.LogBadRequest).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, 0, response.CardID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorResponseLen)
+	}
+
 	if err := db.Joins("Deck").First(&card, response.CardID).Error; err != nil {
 		log := models.CreateLog(fmt.Sprintf("Error on PostResponse: %s from %s", err.Error(), auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
 		_ = log.SendLog()
@@ -353,16 +363,13 @@ func PostResponse(c *fiber.Ctx) error {
 		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
 	}
 
-	validation := new(models.CardResponseValidation)
-
-	if core.ValidateAnswer(response.Response, card) {
-		validation.SetCorrect()
-	} else {
-		validation.SetIncorrect()
-	}
+	validation := core.ValidateResponse(response, card, queries.FetchAnswers(card.ID), queries.IsAskedAsMCQ(auth.User.ID, card, response.Cloze))
 
 	//TODO: Add error handling
-	_ = queries.PostMem(&auth.User, card, validation, response.Training)
+	if result := queries.PostMem(&auth.User, card, response, validation); result.Success {
+		memDate := result.Data.(models.MemDate)
+		queries.AdvanceSession(&auth.User, &memDate, response.Training, validation.Validate)
+	}
 
 	validation.Answer = card.Answer
 
@@ -374,6 +381,277 @@ func PostResponse(c *fiber.Ctx) error {
 	})
 }
 
+// UndoReview method
+// @Description Undo the last review and restore the card to its previous state
+// @Summary undoes the last review
+// @Tags Card
+// @Produce json
+// @Security Beaver
+// @Success 200 {object} models.MemDate
+// @Router /v1/cards/undo [post]
+func UndoReview(c *fiber.Ctx) error {
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	res := queries.UndoLastReview(&auth.User)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error on UndoReview: %s from %s", res.Message, auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, res.Message)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success undo review",
+		Data:    res.Data,
+		Count:   1,
+	})
+}
+
+// OverrideResponse method
+// @Description Accept the last rejected response on a card and re-grade the review
+// @Summary overrides a rejected response
+// @Tags Card
+// @Produce json
+// @Security Beaver
+// @Param id path int true "card id"
+// @Param cloze query int false "cloze number"
+// @Success 200 {object} models.CardResponseValidation
+// @Router /v1/cards/{cardID}/override [post]
+func OverrideResponse(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+	id := c.Params("id")
+	cardID, _ := strconv.ParseUint(id, 10, 32)
+	cloze, _ := strconv.ParseUint(c.Query("cloze", "0"), 10, 32)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	card := new(models.Card)
+
+	if err := db.First(&card, id).Error; err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error on OverrideResponse: %s from %s", err.Error(), auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, uint(cardID))
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusServiceUnavailable, err.Error())
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, card.DeckID, models.AccessStudent); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - OverrideResponse: %s", auth.User.Email, card.DeckID, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	res := queries.OverrideReview(&auth.User, card, uint(cloze))
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error on OverrideResponse: %s from %s", res.Message, auth.User.Email), models.LogBadRequest).SetType(models.LogTypeError).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, res.Message)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success override response",
+		Data:    res.Data,
+		Count:   1,
+	})
+}
+
+// SuspendCard method
+// @Description Suspend a card until it is unsuspended
+// @Summary suspends a card
+// @Tags Card
+// @Produce json
+// @Security Beaver
+// @Param id path int true "card id"
+// @Success 200 {object} models.MemDate
+// @Router /v1/cards/{cardID}/suspend [post]
+func SuspendCard(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+	id := c.Params("id")
+	cardID, _ := strconv.ParseUint(id, 10, 32)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	card := new(models.Card)
+
+	if err := db.First(&card, id).Error; err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error on SuspendCard: %s from %s", err.Error(), auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, uint(cardID))
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusServiceUnavailable, err.Error())
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, card.DeckID, models.AccessStudent); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - SuspendCard: %s", auth.User.Email, card.DeckID, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	res := queries.SuspendMemDate(&auth.User, card.ID, true)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error on SuspendCard: %s from %s", res.Message, auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, res.Message)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success suspend card",
+		Data:    res.Data,
+		Count:   1,
+	})
+}
+
+// UnsuspendCard method
+// @Description Unsuspend a card
+// @Summary unsuspends a card
+// @Tags Card
+// @Produce json
+// @Security Beaver
+// @Param id path int true "card id"
+// @Success 200 {object} models.MemDate
+// @Router /v1/cards/{cardID}/unsuspend [post]
+func UnsuspendCard(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+	id := c.Params("id")
+	cardID, _ := strconv.ParseUint(id, 10, 32)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	card := new(models.Card)
+
+	if err := db.First(&card, id).Error; err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error on UnsuspendCard: %s from %s", err.Error(), auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, uint(cardID))
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusServiceUnavailable, err.Error())
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, card.DeckID, models.AccessStudent); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - UnsuspendCard: %s", auth.User.Email, card.DeckID, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	res := queries.SuspendMemDate(&auth.User, card.ID, false)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error on UnsuspendCard: %s from %s", res.Message, auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, res.Message)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success unsuspend card",
+		Data:    res.Data,
+		Count:   1,
+	})
+}
+
+// BuryCard method
+// @Description Bury a card until tomorrow
+// @Summary buries a card
+// @Tags Card
+// @Produce json
+// @Security Beaver
+// @Param id path int true "card id"
+// @Success 200 {object} models.MemDate
+// @Router /v1/cards/{cardID}/bury [post]
+func BuryCard(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+	id := c.Params("id")
+	cardID, _ := strconv.ParseUint(id, 10, 32)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	card := new(models.Card)
+
+	if err := db.First(&card, id).Error; err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error on BuryCard: %s from %s", err.Error(), auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, uint(cardID))
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusServiceUnavailable, err.Error())
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, card.DeckID, models.AccessStudent); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - BuryCard: %s", auth.User.Email, card.DeckID, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	res := queries.BuryMemDate(&auth.User, card.ID)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error on BuryCard: %s from %s", res.Message, auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, res.Message)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success bury card",
+		Data:    res.Data,
+		Count:   1,
+	})
+}
+
+// ResetCard method
+// @Description Reset the progress on a card
+// @Summary resets a card
+// @Tags Card
+// @Produce json
+// @Security Beaver
+// @Param id path int true "card id"
+// @Success 200 {object} models.MemDate
+// @Router /v1/cards/{cardID}/reset [post]
+func ResetCard(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+	id := c.Params("id")
+	cardID, _ := strconv.ParseUint(id, 10, 32)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	card := new(models.Card)
+
+	if err := db.First(&card, id).Error; err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error on ResetCard: %s from %s", err.Error(), auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, uint(cardID))
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusServiceUnavailable, err.Error())
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, card.DeckID, models.AccessStudent); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - ResetCard: %s", auth.User.Email, card.DeckID, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	res := queries.ResetMemDate(&auth.User, card.ID)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error on ResetCard: %s from %s", res.Message, auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, card.DeckID, card.ID)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, res.Message)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success reset card",
+		Data:    res.Data,
+		Count:   1,
+	})
+}
+
 // PUT
 
 // UpdateCardByID method
@@ -435,6 +713,8 @@ func UpdateCard(c *fiber.Ctx, card *models.Card, user *models.User) *models.Resp
 	db := database.DBConn
 
 	deckID := card.DeckID
+	wasCloze := card.Type == models.CardCloze
+	previous := *card
 
 	res := new(models.ResponseHTTP)
 
@@ -469,6 +749,20 @@ func UpdateCard(c *fiber.Ctx, card *models.Card, user *models.User) *models.Resp
 		mcq.UpdateLinkedAnswers()
 	}
 
+	// The card answer left its previous linked mcq
+	if previous.McqID.Int32 != card.McqID.Int32 {
+		previous.UpdateLinkedMcq()
+	}
+
+	// Clozes added or removed from the question change the subscribers MemDates
+	if wasCloze || card.Type == models.CardCloze {
+		if err := queries.UpdateSubUsers(card, user); err != nil {
+			res.GenerateError(err.Error())
+			return res
+		}
+		queries.DeleteStaleClozes(card)
+	}
+
 	res.GenerateSuccess("Success update card", nil, 0)
 	return res
 }
@@ -515,7 +809,11 @@ func DeleteCardByID(c *fiber.Ctx) error {
 
 	db.Unscoped().Delete(memDates)
 
+	db.Where("answers.card_id = ?", card.ID).Delete(&models.Answer{})
+	db.Where("overrides.card_id = ?", card.ID).Delete(&models.Override{})
+
 	db.Delete(card)
+	card.UpdateLinkedMcq()
 
 	log := models.CreateLog(fmt.Sprintf("Deleted: %d - %s", card.ID, card.Question), models.LogCardDeleted).SetType(models.LogTypeInfo).AttachIDs(auth.User.ID, card.DeckID, card.ID)
 	_ = log.SendLog()
@@ -204,6 +204,135 @@ func GetAllSubUsers(c *fiber.Ctx) error { // Params
 	})
 }
 
+// GetDeckStatistics method to get the user statistics on a deck
+// @Description Get the user review statistics on a deck
+// @Summary gets the deck statistics
+// @Tags Deck
+// @Produce json
+// @Param deckID path string true "Deck ID"
+// @Param days query int false "Number of days (default 30)"
+// @Security Beaver
+// @Success 200 {object} models.Statistics
+// @Router /v1/decks/{deckID}/stats [get]
+func GetDeckStatistics(c *fiber.Ctx) error {
+	// Params
+	deckID := c.Params("deckID")
+	deckidInt, _ := strconv.ParseUint(deckID, 10, 32)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, uint(deckidInt), models.AccessStudent); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - GetDeckStatistics: %s", auth.User.Email, deckidInt, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	days := utils.DefaultStatisticsDays
+	if c.Query("days") != "" {
+		var err error
+		if days, err = strconv.Atoi(c.Query("days")); err != nil || days < 1 || days > utils.MaxStatisticsDays {
+			return queries.RequestError(c, http.StatusBadRequest, utils.ErrorStatisticsDays)
+		}
+	}
+
+	res := queries.FetchStatistics(&auth.User, uint(deckidInt), days)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on GetDeckStatistics: %s", auth.User.Email, res.Message), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, utils.ErrorRequestFailed)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Get deck statistics",
+		Data:    res.Data,
+		Count:   res.Count,
+	})
+}
+
+// GetDeckLeeches method
+// @Description Get the cards of a deck with the highest leech rate across subscribers
+// @Summary gets the deck leech report
+// @Tags Deck
+// @Produce json
+// @Success 200 {array} models.LeechReport
+// @Param deckID path string true "Deck ID"
+// @Security Beaver
+// @Router /v1/decks/{deckID}/leeches [get]
+func GetDeckLeeches(c *fiber.Ctx) error {
+	// Params
+	deckID := c.Params("deckID")
+	deckidInt, _ := strconv.ParseUint(deckID, 10, 32)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, uint(deckidInt), models.AccessOwner); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - GetDeckLeeches: %s", auth.User.Email, deckidInt, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	res := queries.FetchLeechReport(uint(deckidInt))
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on GetDeckLeeches: %s", auth.User.Email, res.Message), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, utils.ErrorRequestFailed)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Get deck leech report",
+		Data:    res.Data,
+		Count:   res.Count,
+	})
+}
+
+// GetDeckOverrides method
+// @Description Get the responses of a deck most frequently overridden as correct by learners
+// @Summary gets the deck override report
+// @Tags Deck
+// @Produce json
+// @Success 200 {array} models.OverrideReport
+// @Param deckID path string true "Deck ID"
+// @Security Beaver
+// @Router /v1/decks/{deckID}/overrides [get]
+func GetDeckOverrides(c *fiber.Ctx) error {
+	// Params
+	deckID := c.Params("deckID")
+	deckidInt, _ := strconv.ParseUint(deckID, 10, 32)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, uint(deckidInt), models.AccessEditor); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - GetDeckOverrides: %s", auth.User.Email, deckidInt, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	res := queries.FetchOverrideReport(uint(deckidInt))
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on GetDeckOverrides: %s", auth.User.Email, res.Message), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, utils.ErrorRequestFailed)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Get deck override report",
+		Data:    res.Data,
+		Count:   res.Count,
+	})
+}
+
 // GetAllAvailableDecks method to get a list of deck
 // @Description Get all public deck that you are not sub to
 // @Summary get a list of deck
@@ -453,6 +582,46 @@ func SubToPrivateDeck(c *fiber.Ctx) error {
 	})
 }
 
+// ResetDeck method
+// @Description Reset the progress on every card of a deck
+// @Summary resets a deck
+// @Tags Deck
+// @Produce json
+// @Success 200
+// @Param deckID path string true "Deck ID"
+// @Security Beaver
+// @Router /v1/decks/{deckID}/reset [post]
+func ResetDeck(c *fiber.Ctx) error {
+	id := c.Params("deckID")
+	deckidInt, _ := strconv.ParseUint(id, 10, 32)
+
+	// Check auth
+	auth := CheckAuth(c, models.PermUser)
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, uint(deckidInt), models.AccessStudent); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - ResetDeck: %s", auth.User.Email, deckidInt, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	res := queries.ResetDeckMemDates(&auth.User, uint(deckidInt))
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on ResetDeck: %s", auth.User.Email, res.Message), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, utils.ErrorRequestFailed)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success reset deck",
+		Data:    nil,
+		Count:   res.Count,
+	})
+}
+
 // PublishDeckRequest method
 // @Description Request to publish deck
 // @Summary publishes a deck
@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/app/queries"
+	"github.com/memnix/memnixrest/pkg/database"
+	"github.com/memnix/memnixrest/pkg/utils"
+	"net/http"
+	"strconv"
+)
+
+// GET
+
+// GetExamByID method
+// @Description Get an exam with its questions. Answers are only set once the exam is submitted
+// @Summary gets an exam
+// @Tags Exam
+// @Produce json
+// @Security Beaver
+// @Param id path int true "exam id"
+// @Success 200 {object} models.Exam
+// @Router /v1/exams/{examID} [get]
+func GetExamByID(c *fiber.Ctx) error {
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	id, _ := strconv.ParseUint(c.Params("id"), 10, 32)
+
+	exam, err := queries.FetchExam(auth.User.ID, uint(id))
+	if err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on GetExamByID: %s", auth.User.Email, err.Error()), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorRequestFailed)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success get exam by ID",
+		Data:    *exam,
+		Count:   len(exam.Questions),
+	})
+}
+
+// GetDeckExams method
+// @Description Get the latest exams of the user on a deck to compare attempts
+// @Summary gets the user exams on a deck
+// @Tags Exam
+// @Produce json
+// @Security Beaver
+// @Param deckID path string true "Deck ID"
+// @Success 200 {array} models.Exam
+// @Router /v1/decks/{deckID}/exams [get]
+func GetDeckExams(c *fiber.Ctx) error {
+	// Params
+	deckID := c.Params("deckID")
+	deckidInt, _ := strconv.ParseUint(deckID, 10, 32)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	exams, err := queries.FetchUserExams(auth.User.ID, uint(deckidInt))
+	if err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on GetDeckExams: %s", auth.User.Email, err.Error()), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, utils.ErrorRequestFailed)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Get deck exams",
+		Data:    exams,
+		Count:   len(exams),
+	})
+}
+
+// GetDeckExamResults method
+// @Description Get the latest submitted exams of every learner on a deck
+// @Summary gets the deck exam results
+// @Tags Exam
+// @Produce json
+// @Security Beaver
+// @Param deckID path string true "Deck ID"
+// @Success 200 {array} models.ExamResult
+// @Router /v1/decks/{deckID}/exams/results [get]
+func GetDeckExamResults(c *fiber.Ctx) error {
+	// Params
+	deckID := c.Params("deckID")
+	deckidInt, _ := strconv.ParseUint(deckID, 10, 32)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, uint(deckidInt), models.AccessEditor); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - GetDeckExamResults: %s", auth.User.Email, deckidInt, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	results, err := queries.FetchExamResults(uint(deckidInt))
+	if err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on GetDeckExamResults: %s", auth.User.Email, err.Error()), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, utils.ErrorRequestFailed)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Get deck exam results",
+		Data:    results,
+		Count:   len(results),
+	})
+}
+
+// POST
+
+// CreateExam method
+// @Description Generate an exam on random cards of a deck
+// @Summary creates an exam
+// @Tags Exam
+// @Produce json
+// @Accept json
+// @Param exam body models.ExamConfig true "Exam to generate"
+// @Security Beaver
+// @Success 200 {object} models.Exam
+// @Router /v1/exams/new [post]
+func CreateExam(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	config := new(models.ExamConfig)
+
+	if err := c.BodyParser(&config); err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on CreateExam: %s", auth.User.Email, err.Error()), models.LogBodyParserError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, err.Error())
+	}
+
+	if config.NotValidate() {
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorExamConfig)
+	}
+
+	if res := queries.CheckAccess(auth.User.ID, config.DeckID, models.AccessStudent); !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - CreateExam: %s", auth.User.Email, config.DeckID, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, config.DeckID, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+	}
+
+	deck := new(models.Deck)
+	if err := db.First(&deck, config.DeckID).Error; err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on CreateExam: %s", auth.User.Email, err.Error()), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, config.DeckID, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, utils.ErrorRequestFailed)
+	}
+
+	res := queries.CreateExam(&auth.User, deck, config)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on CreateExam: %s", auth.User.Email, res.Message), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, config.DeckID, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, res.Message)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success create exam",
+		Data:    res.Data,
+		Count:   res.Count,
+	})
+}
+
+// SubmitExam method
+// @Description Submit every response of an exam and get the scored report
+// @Summary submits an exam
+// @Tags Exam
+// @Produce json
+// @Accept json
+// @Param id path int true "exam id"
+// @Param submission body models.ExamSubmission true "Exam responses"
+// @Security Beaver
+// @Success 200 {object} models.Exam
+// @Router /v1/exams/{examID}/submit [post]
+func SubmitExam(c *fiber.Ctx) error {
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	id, _ := strconv.ParseUint(c.Params("id"), 10, 32)
+
+	submission := new(models.ExamSubmission)
+
+	if err := c.BodyParser(&submission); err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on SubmitExam: %s", auth.User.Email, err.Error()), models.LogBodyParserError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, err.Error())
+	}
+
+	if submission.NotValidate() {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on SubmitExam: BadRequest", auth.User.Email), models.LogBadRequest).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorResponseLen)
+	}
+
+	exam, err := queries.FetchExam(auth.User.ID, uint(id))
+	if err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on SubmitExam: %s", auth.User.Email, err.Error()), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorRequestFailed)
+	}
+
+	res := queries.SubmitExam(&auth.User, exam, submission)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on SubmitExam: %s", auth.User.Email, res.Message), models.LogBadRequest).SetType(models.LogTypeError).AttachIDs(auth.User.ID, exam.DeckID, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, res.Message)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success submit exam",
+		Data:    res.Data,
+		Count:   res.Count,
+	})
+}
@@ -33,7 +33,7 @@ func GetMcqsByDeck(c *fiber.Ctx) error {
 
 	var mcqs []models.Mcq
 
-	if err := db.Joins("Deck").Where("mcqs.deck_id = ?", deckID).Find(&mcqs).Error; err != nil {
+	if err := db.Joins("Deck").Preload("Options").Where("mcqs.deck_id = ?", deckID).Find(&mcqs).Error; err != nil {
 		deckidInt, _ := strconv.ParseUint(deckID, 10, 32)
 		log := models.CreateLog(fmt.Sprintf("Error from %s on GetMcqsByDeck: %s", auth.User.Email, err.Error()), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, uint(deckidInt), 0)
 		_ = log.SendLog()
@@ -89,7 +89,7 @@ func CreateMcq(c *fiber.Ctx) error {
 	if mcq.NotValidate() {
 		log := models.CreateLog(fmt.Sprintf("Error from %s on CreateMcq: BadRequest", auth.User.Email), models.LogBadRequest).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
 		_ = log.SendLog()
-		return queries.RequestError(c, http.StatusBadRequest, "You must provide at least 3 and at most 150 answers for Standalone MCQ")
+		return queries.RequestError(c, http.StatusBadRequest, fmt.Sprintf("You must provide at least %d and at most %d options for Standalone MCQ", utils.MinMcqOptions, utils.MaxMcqPool))
 	}
 
 	db.Create(mcq)
@@ -178,16 +178,19 @@ func UpdateMcq(c *fiber.Ctx, mcq *models.Mcq) *models.ResponseHTTP {
 		return res
 	}
 
-	if mcq.Type == models.McqStandalone && (len(mcq.Answers) < utils.MinMcqAnswersLen || len(mcq.Answers) > utils.MaxMcqAnswersLen) || len(mcq.Name) > utils.MaxMcqName || mcq.Name == "" {
+	if mcq.NotValidate() {
 		res.GenerateError(utils.ErrorRequestFailed)
 		return res
 	}
 
 	if mcq.Type == models.McqLinked {
 		mcq.UpdateLinkedAnswers()
+	} else if err := mcq.SaveOptions(); err != nil {
+		res.GenerateError(err.Error())
+		return res
 	}
 
-	db.Save(mcq)
+	db.Omit("Options").Save(mcq)
 
 	res.GenerateSuccess("Success update mcq", nil, 0)
 	return res
@@ -223,6 +226,7 @@ func DeleteMcqByID(c *fiber.Ctx) error {
 		return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
 	}
 
+	db.Where("mcq_options.mcq_id = ?", mcq.ID).Delete(&models.McqOption{})
 	db.Delete(mcq)
 
 	log := models.CreateLog(fmt.Sprintf("Deleted: %d - %s", mcq.ID, mcq.Name), models.LogCardDeleted).SetType(models.LogTypeInfo).AttachIDs(auth.User.ID, mcq.DeckID, 0)
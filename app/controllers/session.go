@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/app/queries"
+	"github.com/memnix/memnixrest/pkg/utils"
+	"net/http"
+	"strconv"
+)
+
+// GET
+
+// GetCurrentSession method
+// @Description Get the active study session and its current card, to resume it on any device
+// @Summary gets the active session
+// @Tags Session
+// @Produce json
+// @Security Beaver
+// @Success 200 {object} models.SessionResponse
+// @Router /v1/sessions/current [get]
+func GetCurrentSession(c *fiber.Ctx) error {
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	session, err := queries.FetchActiveSession(auth.User.ID)
+	if err != nil {
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorNoSession)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success get current session",
+		Data:    queries.GenerateSessionResponse(&auth.User, session),
+		Count:   1,
+	})
+}
+
+// GetSessionSummary method
+// @Description Get the summary of a study session
+// @Summary gets a session summary
+// @Tags Session
+// @Produce json
+// @Security Beaver
+// @Param id path int true "session id"
+// @Success 200 {object} models.SessionSummary
+// @Router /v1/sessions/{sessionID}/summary [get]
+func GetSessionSummary(c *fiber.Ctx) error {
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	id, _ := strconv.ParseUint(c.Params("id"), 10, 32)
+
+	session, err := queries.FetchSession(auth.User.ID, uint(id))
+	if err != nil {
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorNoSession)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success get session summary",
+		Data:    session.GetSummary(),
+		Count:   1,
+	})
+}
+
+// POST
+
+// CreateSession method
+// @Description Start a study session holding the queue server-side. The active session is finished
+// @Summary starts a session
+// @Tags Session
+// @Produce json
+// @Accept json
+// @Param session body models.SessionConfig true "Session to start"
+// @Security Beaver
+// @Success 200 {object} models.SessionResponse
+// @Router /v1/sessions/new [post]
+func CreateSession(c *fiber.Ctx) error {
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	config := new(models.SessionConfig)
+
+	if err := c.BodyParser(&config); err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on CreateSession: %s", auth.User.Email, err.Error()), models.LogBodyParserError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, err.Error())
+	}
+
+	if config.Training && config.DeckID == 0 {
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorBreak)
+	}
+
+	if config.DeckID != 0 {
+		if res := queries.CheckAccess(auth.User.ID, config.DeckID, models.AccessStudent); !res.Success {
+			log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - CreateSession: %s", auth.User.Email, config.DeckID, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, config.DeckID, 0)
+			_ = log.SendLog()
+			return queries.RequestError(c, http.StatusForbidden, utils.ErrorForbidden)
+		}
+	}
+
+	res := queries.CreateSession(&auth.User, config.DeckID, config.Training)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on CreateSession: %s", auth.User.Email, res.Message), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, config.DeckID, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, res.Message)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success create session",
+		Data:    res.Data,
+		Count:   1,
+	})
+}
+
+// EndSession method
+// @Description Finish a study session and get its summary
+// @Summary ends a session
+// @Tags Session
+// @Produce json
+// @Security Beaver
+// @Param id path int true "session id"
+// @Success 200 {object} models.SessionSummary
+// @Router /v1/sessions/{sessionID}/end [post]
+func EndSession(c *fiber.Ctx) error {
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	id, _ := strconv.ParseUint(c.Params("id"), 10, 32)
+
+	res := queries.EndSession(&auth.User, uint(id))
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on EndSession: %s", auth.User.Email, res.Message), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, res.Message)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success end session",
+		Data:    res.Data,
+		Count:   1,
+	})
+}
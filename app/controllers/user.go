@@ -83,6 +83,82 @@ func GetUserByID(c *fiber.Ctx) error {
 	})
 }
 
+// GetForecast method to get the review forecast
+// @Description Get the number of cards due for each of the next days, by deck and learning stage
+// @Summary gets the review forecast
+// @Tags User
+// @Produce json
+// @Param days query int false "Number of days (default 7)"
+// @Security Beaver
+// @Success 200 {array} models.ForecastDay
+// @Router /v1/users/forecast [get]
+func GetForecast(c *fiber.Ctx) error {
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	days := utils.DefaultForecastDays
+	if c.Query("days") != "" {
+		var err error
+		if days, err = strconv.Atoi(c.Query("days")); err != nil || days < 1 || days > utils.MaxForecastDays {
+			return queries.RequestError(c, http.StatusBadRequest, utils.ErrorForecastDays)
+		}
+	}
+
+	res := queries.FetchForecast(&auth.User, days)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error on GetForecast: %s from %s", res.Message, auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, utils.ErrorRequestFailed)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Get forecast",
+		Data:    res.Data,
+		Count:   res.Count,
+	})
+}
+
+// GetStatistics method to get the user statistics
+// @Description Get the user review statistics on every deck
+// @Summary gets the user statistics
+// @Tags User
+// @Produce json
+// @Param days query int false "Number of days (default 30)"
+// @Security Beaver
+// @Success 200 {object} models.Statistics
+// @Router /v1/users/stats [get]
+func GetStatistics(c *fiber.Ctx) error {
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	days := utils.DefaultStatisticsDays
+	if c.Query("days") != "" {
+		var err error
+		if days, err = strconv.Atoi(c.Query("days")); err != nil || days < 1 || days > utils.MaxStatisticsDays {
+			return queries.RequestError(c, http.StatusBadRequest, utils.ErrorStatisticsDays)
+		}
+	}
+
+	res := queries.FetchStatistics(&auth.User, 0, days)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Error on GetStatistics: %s from %s", res.Message, auth.User.Email), models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusInternalServerError, utils.ErrorRequestFailed)
+	}
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Get statistics",
+		Data:    res.Data,
+		Count:   res.Count,
+	})
+}
+
 // SetTodayConfig method to set a config
 // @Description Set the today config for a deck
 // @Summary sets the today config for a deck
@@ -136,6 +212,212 @@ func SetTodayConfig(c *fiber.Ctx) error {
 	})
 }
 
+// SetLimitsConfig method to set the daily limits of a deck
+// @Description Set the max new cards and the max reviews per day for a deck
+// @Summary sets the daily limits for a deck
+// @Tags User
+// @Produce json
+// @Accept json
+// @Param deckId path int true "Deck ID"
+// @Param config body models.DeckLimitsConfig true "Deck Limits Config"
+// @Security Beaver
+// @Success 200
+// @Router /v1/users/settings/{deckId}/limits [post]
+func SetLimitsConfig(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+
+	// Params
+	deckID := c.Params("deckID")
+	deckidInt, _ := strconv.ParseUint(deckID, 10, 32)
+
+	limitsConfig := new(models.DeckLimitsConfig)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	if err := c.BodyParser(&limitsConfig); err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error on SetLimitsConfig: %s from %s", err.Error(), auth.User.Email), models.LogBodyParserError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, err.Error())
+	}
+
+	if limitsConfig.NotValidate() {
+		log := models.CreateLog(fmt.Sprintf("Error on SetLimitsConfig: BadRequest from %s", auth.User.Email), models.LogBadRequest).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorDailyLimits)
+	}
+
+	res := queries.CheckAccess(auth.User.ID, uint(deckidInt), models.AccessStudent)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - SetLimitsConfig: %s", auth.User.Email, deckidInt, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorNotSub)
+	}
+
+	access := res.Data.(models.Access)
+
+	access.MaxNew = limitsConfig.MaxNew
+	access.MaxReviews = limitsConfig.MaxReviews
+
+	db.Save(&access)
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success updated deck limits",
+		Data:    nil,
+		Count:   1,
+	})
+}
+
+// SetExamDateConfig method to set the exam date of a deck
+// @Description Set the exam date of a deck. Intervals are compressed so every card is seen again before it
+// @Summary sets the exam date for a deck
+// @Tags User
+// @Produce json
+// @Accept json
+// @Param deckId path int true "Deck ID"
+// @Param config body models.ExamDateConfig true "Exam Date Config"
+// @Security Beaver
+// @Success 200
+// @Router /v1/users/settings/{deckId}/exam [post]
+func SetExamDateConfig(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+
+	// Params
+	deckID := c.Params("deckID")
+	deckidInt, _ := strconv.ParseUint(deckID, 10, 32)
+
+	examDateConfig := new(models.ExamDateConfig)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	if err := c.BodyParser(&examDateConfig); err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error on SetExamDateConfig: %s from %s", err.Error(), auth.User.Email), models.LogBodyParserError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, err.Error())
+	}
+
+	if examDateConfig.NotValidate(auth.User.GetDayStart(time.Now())) {
+		log := models.CreateLog(fmt.Sprintf("Error on SetExamDateConfig: BadRequest from %s", auth.User.Email), models.LogBadRequest).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorExamDate)
+	}
+
+	res := queries.CheckAccess(auth.User.ID, uint(deckidInt), models.AccessStudent)
+	if !res.Success {
+		log := models.CreateLog(fmt.Sprintf("Forbidden from %s on deck %d - SetExamDateConfig: %s", auth.User.Email, deckidInt, res.Message), models.LogPermissionForbidden).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, uint(deckidInt), 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusForbidden, utils.ErrorNotSub)
+	}
+
+	access := res.Data.(models.Access)
+
+	access.ExamDate = examDateConfig.ExamDate
+
+	db.Save(&access)
+
+	// Cards already scheduled past the exam are pulled back before it
+	queries.CramMemDates(&auth.User, access.DeckID, access.ExamDate)
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success updated deck exam date",
+		Data:    nil,
+		Count:   1,
+	})
+}
+
+// SetTimeConfig method to set the user time config
+// @Description Set the timezone and the day rollover hour of the user
+// @Summary sets the user time config
+// @Tags User
+// @Produce json
+// @Accept json
+// @Param config body models.TimeConfig true "Time Config"
+// @Security Beaver
+// @Success 200
+// @Router /v1/users/settings/time [post]
+func SetTimeConfig(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+
+	timeConfig := new(models.TimeConfig)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	if err := c.BodyParser(&timeConfig); err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error on SetTimeConfig: %s from %s", err.Error(), auth.User.Email), models.LogBodyParserError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, err.Error())
+	}
+
+	if timeConfig.NotValidate() {
+		log := models.CreateLog(fmt.Sprintf("Error on SetTimeConfig: BadRequest from %s", auth.User.Email), models.LogBadRequest).SetType(models.LogTypeWarning).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, utils.ErrorTimeConfig)
+	}
+
+	auth.User.Timezone = timeConfig.Timezone
+	auth.User.DayStart = timeConfig.DayStart
+
+	db.Save(&auth.User)
+
+	log := models.CreateLog(fmt.Sprintf("Edited time config: %s - %s (%d)", auth.User.Email, auth.User.Timezone, auth.User.DayStart), models.LogUserEdit).SetType(models.LogTypeInfo).AttachIDs(auth.User.ID, 0, 0)
+	_ = log.SendLog()
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success updated time config",
+		Data:    nil,
+		Count:   1,
+	})
+}
+
+// SetBalanceConfig method to set the user load balancing config
+// @Description Enable or disable review load balancing for the user
+// @Summary sets the user load balancing config
+// @Tags User
+// @Produce json
+// @Accept json
+// @Param config body models.BalanceConfig true "Balance Config"
+// @Security Beaver
+// @Success 200
+// @Router /v1/users/settings/balance [post]
+func SetBalanceConfig(c *fiber.Ctx) error {
+	db := database.DBConn // DB Conn
+
+	balanceConfig := new(models.BalanceConfig)
+
+	auth := CheckAuth(c, models.PermUser) // Check auth
+	if !auth.Success {
+		return queries.AuthError(c, &auth)
+	}
+
+	if err := c.BodyParser(&balanceConfig); err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error on SetBalanceConfig: %s from %s", err.Error(), auth.User.Email), models.LogBodyParserError).SetType(models.LogTypeError).AttachIDs(auth.User.ID, 0, 0)
+		_ = log.SendLog()
+		return queries.RequestError(c, http.StatusBadRequest, err.Error())
+	}
+
+	auth.User.LoadBalance = balanceConfig.LoadBalance
+
+	db.Save(&auth.User)
+
+	return c.Status(http.StatusOK).JSON(models.ResponseHTTP{
+		Success: true,
+		Message: "Success updated balance config",
+		Data:    nil,
+		Count:   1,
+	})
+}
+
 // ResetPassword method to request a password reset
 // @Description Request a password reset
 // @Summary gets a code to reset a password
@@ -3,6 +3,7 @@ package models
 import (
 	"github.com/memnix/memnixrest/pkg/utils"
 	"gorm.io/gorm"
+	"time"
 )
 
 // Access structure
@@ -14,6 +15,12 @@ type Access struct {
 	Deck        Deck             `swaggerignore:"true"`
 	Permission  AccessPermission `json:"permission" example:"0"` // 0: None - 1: Student - 2: Editor - 3: Owner
 	ToggleToday bool             `json:"today" gorm:"default:true"`
+	MaxNew      uint             `json:"max_new" example:"20" gorm:"default:20"`       // Max new cards per day
+	MaxReviews  uint             `json:"max_reviews" example:"200" gorm:"default:200"` // Max reviews per day
+	NewToday    uint             `json:"new_today" example:"0"`
+	ReviewToday uint             `json:"review_today" example:"0"`
+	CounterDate time.Time        `json:"-" swaggerignore:"true"`
+	ExamDate    time.Time        `json:"exam_date" example:"01/01/2000"` // Cards are seen again before this day, zero: no exam
 }
 
 // AccessPermission  enum type
@@ -47,4 +54,58 @@ func (access *Access) Set(userID, deckID uint, permission AccessPermission) {
 	access.Permission = permission
 }
 
+// ResetDailyCounters resets today's counters if they were set before dayStart
+func (access *Access) ResetDailyCounters(dayStart time.Time) {
+	if access.CounterDate.Before(dayStart) {
+		access.NewToday = 0
+		access.ReviewToday = 0
+		access.CounterDate = dayStart
+	}
+}
+
+// AddTodayReview increments today's counters
+func (access *Access) AddTodayReview(dayStart time.Time, isNew bool) {
+	access.ResetDailyCounters(dayStart)
+
+	if isNew {
+		access.NewToday++
+	} else {
+		access.ReviewToday++
+	}
+}
+
+// RemoveTodayReview decrements today's counters
+// Counters from a previous day are left untouched
+func (access *Access) RemoveTodayReview(dayStart time.Time, isNew bool) {
+	if access.CounterDate.Before(dayStart) {
+		return
+	}
+
+	if isNew && access.NewToday > 0 {
+		access.NewToday--
+	} else if !isNew && access.ReviewToday > 0 {
+		access.ReviewToday--
+	}
+}
+
+// GetRemainingNew returns how many new cards can still be seen today
+func (access *Access) GetRemainingNew(dayStart time.Time) uint {
+	access.ResetDailyCounters(dayStart)
+
+	if access.NewToday >= access.MaxNew {
+		return 0
+	}
+	return access.MaxNew - access.NewToday
+}
+
+// GetRemainingReviews returns how many reviews can still be done today
+func (access *Access) GetRemainingReviews(dayStart time.Time) uint {
+	access.ResetDailyCounters(dayStart)
+
+	if access.ReviewToday >= access.MaxReviews {
+		return 0
+	}
+	return access.MaxReviews - access.ReviewToday
+}
+
 // TODO add setter for AccessPermission
@@ -6,26 +6,39 @@ import (
 	"github.com/memnix/memnixrest/pkg/database"
 	"github.com/memnix/memnixrest/pkg/utils"
 	"gorm.io/gorm"
-	"math/rand"
-	"time"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// clozeRegexp matches {{c1::answer}} and {{c1::answer::hint}} markers
+var clozeRegexp = regexp.MustCompile(`\{\{c(\d+)::(.*?)(?:::(.*?))?\}\}`)
+
 // Card structure
 type Card struct {
-	gorm.Model       `swaggerignore:"true"`
-	Question         string        `json:"card_question" example:"What's the answer to life ?"`
-	Answer           string        `json:"card_answer" example:"42"`
-	DeckID           uint          `json:"deck_id" example:"1"`
-	Deck             Deck          `swaggerignore:"true" json:"-"`
-	Type             CardType      `json:"card_type" example:"0" gorm:"type:Int"`
-	Format           string        `json:"card_format" example:"Date / Name / Country"`
-	Image            string        `json:"card_image"` // Should be an url
-	Case             bool          `json:"card_case" gorm:"default:false"`
-	Spaces           bool          `json:"card_spaces" gorm:"default:false"`
-	Explication      string        `json:"card_explication"`
-	ExplicationImage string        `json:"card_explication_image"`
-	McqID            sql.NullInt32 `json:"mcq_id" swaggerignore:"true"`
-	Mcq              Mcq           `swaggerignore:"true" json:"-"`
+	gorm.Model        `swaggerignore:"true"`
+	Question          string        `json:"card_question" example:"What's the answer to life ?"`
+	Answer            string        `json:"card_answer" example:"42"`
+	DeckID            uint          `json:"deck_id" example:"1"`
+	Deck              Deck          `swaggerignore:"true" json:"-"`
+	Type              CardType      `json:"card_type" example:"0" gorm:"type:Int"`
+	Format            string        `json:"card_format" example:"Date / Name / Country"`
+	Image             string        `json:"card_image"` // Should be an url
+	Case              bool          `json:"card_case" gorm:"default:false"`
+	Spaces            bool          `json:"card_spaces" gorm:"default:false"`
+	AllowTypos        bool          `json:"card_allow_typos" gorm:"default:false"`        // Near-misses are accepted as almost correct
+	IgnoreAccents     bool          `json:"card_ignore_accents" gorm:"default:false"`     // Diacritics are ignored
+	IgnorePunctuation bool          `json:"card_ignore_punctuation" gorm:"default:false"` // Punctuation is ignored
+	IgnoreArticles    bool          `json:"card_ignore_articles" gorm:"default:false"`    // Leading articles of Deck.Lang are ignored
+	Tolerance         float64       `json:"card_tolerance" example:"0" gorm:"default:0"`  // CardInt accepted error
+	RelativeTolerance bool          `json:"card_relative_tolerance" gorm:"default:false"` // Tolerance is a fraction of the answer
+	AcceptUnits       bool          `json:"card_accept_units" gorm:"default:false"`       // CardInt response may use another unit of the same dimension
+	FieldTypes        string        `json:"card_field_types" example:"1 0 0"`             // CardType of each Format field, Card.Type by default
+	Explication       string        `json:"card_explication"`
+	ExplicationImage  string        `json:"card_explication_image"`
+	McqID             sql.NullInt32 `json:"mcq_id" swaggerignore:"true"`
+	Mcq               Mcq           `swaggerignore:"true" json:"-"`
 }
 
 // CardType enum type
@@ -35,14 +48,130 @@ const (
 	CardString CardType = iota
 	CardInt
 	CardMCQ
+	CardExpression
+	CardCloze
 )
 
 // NotValidate performs validation of the Card
 func (card *Card) NotValidate() bool {
-	return len(card.Question) < utils.MinCardQuestionLen || card.Answer == "" || (card.Type == CardMCQ && card.McqID.Int32 == 0) || len(
+	return len(card.Question) < utils.MinCardQuestionLen || (card.Answer == "" && card.Type != CardCloze) || (card.Type == CardCloze && (len(card.GetClozes()) == 0 || card.McqID.Int32 != 0)) || (card.Type == CardMCQ && card.McqID.Int32 == 0) || len(
 		card.Format) > utils.MaxCardFormatLen || len(
 		card.Question) > utils.MaxDefaultLen || len(card.Answer) > utils.MaxDefaultLen || len(card.Image) > utils.MaxImageURLLen || len(
-		card.ExplicationImage) > utils.MaxImageURLLen || len(card.Explication) > utils.MaxCardExplicationLen
+		card.ExplicationImage) > utils.MaxImageURLLen || len(card.Explication) > utils.MaxCardExplicationLen || card.Tolerance < 0 || (card.Type == CardInt && !validQuantity(card.Answer)) || (card.Type == CardExpression && !validExpression(card.Answer)) || !card.validFieldTypes()
+}
+
+// GetFields returns the field names of a multi-field card from Card.Format
+// The card is multi-field only if Card.Answer has as many fields as Card.Format
+func (card *Card) GetFields() []string {
+	if card.Type == CardMCQ || card.Type == CardCloze {
+		return nil
+	}
+
+	fields := SplitFields(card.Format)
+	if len(fields) < 2 || len(SplitFields(card.Answer)) != len(fields) {
+		return nil
+	}
+
+	return fields
+}
+
+// GetFieldType returns the CardType used to check a field
+func (card *Card) GetFieldType(field int) CardType {
+	types := strings.Fields(card.FieldTypes)
+	if field >= len(types) {
+		return card.Type
+	}
+
+	fieldType, err := strconv.ParseInt(types[field], 10, 64)
+	if err != nil {
+		return card.Type
+	}
+
+	return CardType(fieldType)
+}
+
+// validFieldTypes returns if every field type can check a single field
+func (card *Card) validFieldTypes() bool {
+	for _, value := range strings.Fields(card.FieldTypes) {
+		fieldType, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || (CardType(fieldType) != CardString && CardType(fieldType) != CardInt && CardType(fieldType) != CardExpression) {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitFields splits a multi-field answer on utils.FieldSeparator
+func SplitFields(answer string) []string {
+	fields := strings.Split(answer, utils.FieldSeparator)
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
+}
+
+// validExpression returns if a CardExpression answer is a math expression
+func validExpression(answer string) bool {
+	_, ok := utils.ParseExpression(answer)
+	return ok
+}
+
+// GetClozes returns the sorted cloze numbers of a CardCloze question
+// Other cards have a single item numbered 0
+func (card *Card) GetClozes() []uint {
+	if card.Type != CardCloze {
+		return []uint{0}
+	}
+
+	seen := make(map[uint]bool)
+	clozes := make([]uint, 0)
+
+	for _, match := range clozeRegexp.FindAllStringSubmatch(card.Question, -1) {
+		number, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || number == 0 || seen[uint(number)] {
+			continue
+		}
+		seen[uint(number)] = true
+		clozes = append(clozes, uint(number))
+	}
+
+	sort.Slice(clozes, func(i, j int) bool { return clozes[i] < clozes[j] })
+
+	return clozes
+}
+
+// GetClozeQuestion returns the question with the given cloze masked and the other clozes revealed
+func (card *Card) GetClozeQuestion(cloze uint) string {
+	return clozeRegexp.ReplaceAllStringFunc(card.Question, func(marker string) string {
+		match := clozeRegexp.FindStringSubmatch(marker)
+		if match[1] != strconv.FormatUint(uint64(cloze), 10) {
+			return match[2]
+		}
+		if match[3] != "" {
+			return "[" + match[3] + "]"
+		}
+		return "[...]"
+	})
+}
+
+// GetClozeAnswer returns the hidden text of a cloze
+// Several spans with the same number are joined with ", "
+func (card *Card) GetClozeAnswer(cloze uint) string {
+	spans := make([]string, 0)
+
+	for _, match := range clozeRegexp.FindAllStringSubmatch(card.Question, -1) {
+		if match[1] == strconv.FormatUint(uint64(cloze), 10) {
+			spans = append(spans, match[2])
+		}
+	}
+
+	return strings.Join(spans, ", ")
+}
+
+// validQuantity returns if a CardInt answer is a number with an optional unit
+func validQuantity(answer string) bool {
+	_, ok := utils.ParseQuantity(answer, "")
+	return ok
 }
 
 // ValidateMCQ makes sure that the mcq attached to a card is correct
@@ -69,6 +198,22 @@ func (card *Card) ValidateMCQ(user *User) (*Mcq, bool) {
 	return nil, true
 }
 
+// UpdateLinkedMcq resyncs the options of the linked mcq attached to the card
+func (card *Card) UpdateLinkedMcq() {
+	db := database.DBConn // DB Conn
+	mcq := new(Mcq)
+
+	if card.McqID.Int32 == 0 {
+		return
+	}
+
+	if err := db.First(&mcq, card.McqID).Error; err != nil || mcq.Type != McqLinked {
+		return
+	}
+
+	mcq.UpdateLinkedAnswers()
+}
+
 // ToString returns CardType value as a string
 func (s CardType) ToString() string {
 	switch s {
@@ -78,55 +223,24 @@ func (s CardType) ToString() string {
 		return "Card Int"
 	case CardMCQ:
 		return "Card MCQ"
+	case CardExpression:
+		return "Card Expression"
+	case CardCloze:
+		return "Card Cloze"
 	default:
 		return utils.UNKNOWN
 	}
 }
 
-// GetMCQAnswers returns mcq's answers
-func (card *Card) GetMCQAnswers() []string {
+// GetMCQPool returns the options of the mcq attached to the card
+func (card *Card) GetMCQPool() []McqOption {
 	db := database.DBConn // DB Conn
 
-	var answers []string
-
 	mcq := new(Mcq)
 
 	if err := db.First(&mcq, card.McqID).Error; err != nil {
-		return answers
-	}
-
-	answersList := mcq.GetAnswers()
-
-	for i := range answersList {
-		if i >= len(answersList) {
-			break
-		}
-		if answersList[i] == card.Answer {
-			answersList[i] = answersList[len(answersList)-1]
-			answersList = answersList[:len(answersList)-1]
-		}
+		return make([]McqOption, 0)
 	}
 
-	if len(answersList) < 3 {
-		return make([]string, 0)
-	}
-
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(answersList), func(i, j int) { answersList[i], answersList[j] = answersList[j], answersList[i] })
-
-	i, c := 0, 0
-	for i < 3 {
-		if answersList[c] != card.Answer {
-			answers = append(answers, answersList[c])
-			i++
-		}
-		c++
-	}
-
-	answers = append(answers, card.Answer)
-
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(answers), func(i, j int) { answers[i], answers[j] = answers[j], answers[i] })
-
-	return answers
+	return mcq.GetOptions()
 }
@@ -6,20 +6,33 @@ import (
 	"gorm.io/gorm"
 	"math/rand"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Deck structure
 type Deck struct {
-	gorm.Model  `swaggerignore:"true"`
-	Share       bool       `json:"deck_share" example:"true" gorm:"default:false"`
-	Status      DeckStatus `json:"deck_status" example:"2"` // 1: Draft - 2: Private - 3: Published
-	DeckName    string     `json:"deck_name" example:"First Deck"`
-	Description string     `json:"deck_description" example:"A simple demo deck"`
-	Banner      string     `json:"deck_banner" example:"A banner url"`
-	Key         string     `json:"deck_key" example:"MEM"`
-	Code        string     `json:"deck_code" example:"6452"`
-	Lang        string     `json:"deck_lang"`
+	gorm.Model     `swaggerignore:"true"`
+	Share          bool          `json:"deck_share" example:"true" gorm:"default:false"`
+	Status         DeckStatus    `json:"deck_status" example:"2"` // 1: Draft - 2: Private - 3: Published
+	DeckName       string        `json:"deck_name" example:"First Deck"`
+	Description    string        `json:"deck_description" example:"A simple demo deck"`
+	Banner         string        `json:"deck_banner" example:"A banner url"`
+	Key            string        `json:"deck_key" example:"MEM"`
+	Code           string        `json:"deck_code" example:"6452"`
+	Lang           string        `json:"deck_lang"`
+	Scheduler      SchedulerType `json:"deck_scheduler" example:"0" gorm:"default:0"` // 0: SM-2 - 1: FSRS
+	LearnSteps     string        `json:"deck_learn_steps" example:"1m 10m" gorm:"default:1m 10m"`
+	RelearnSteps   string        `json:"deck_relearn_steps" example:"10m" gorm:"default:10m"`
+	LeechThreshold uint          `json:"deck_leech_threshold" example:"8" gorm:"default:8"` // 0: leech detection disabled
+	LeechSuspend   bool          `json:"deck_leech_suspend" example:"false" gorm:"default:false"`
+	McqOptions     uint          `json:"deck_mcq_options" example:"4" gorm:"default:4"`          // Options shown by each mcq question
+	McqPolicy      McqPolicy     `json:"deck_mcq_policy" example:"0" gorm:"default:0"`           // 0: Progressive - 1: Always MCQ - 2: Always typed
+	McqRepetitions uint          `json:"deck_mcq_repetitions" example:"2" gorm:"default:2"`      // Progressive: reviews passed before typed input
+	McqEfactor     float32       `json:"deck_mcq_efactor" example:"2.3" gorm:"default:2.3"`      // Progressive: cards at or below need one more review
+	McqHardEfactor float32       `json:"deck_mcq_hard_efactor" example:"1.7" gorm:"default:1.7"` // Progressive: cards at or below stay MCQ
+	McqSuccess     MemQuality    `json:"deck_mcq_success" example:"4" gorm:"default:4"`          // Quality of a passed MCQ
+	McqFail        MemQuality    `json:"deck_mcq_fail" example:"2" gorm:"default:2"`             // Quality of a failed MCQ out of learning
 }
 
 // DeckStatus enum type
@@ -45,11 +58,149 @@ func (s DeckStatus) ToString() string {
 	}
 }
 
+// SchedulerType enum type
+type SchedulerType int64
+
+const (
+	SchedulerSM2 SchedulerType = iota
+	SchedulerFSRS
+)
+
+// ToString returns SchedulerType value as a string
+func (s SchedulerType) ToString() string {
+	switch s {
+	case SchedulerSM2:
+		return "Scheduler SM-2"
+	case SchedulerFSRS:
+		return "Scheduler FSRS"
+	default:
+		return utils.UNKNOWN
+	}
+}
+
+// McqPolicy enum type
+type McqPolicy int64
+
+const (
+	McqPolicyProgressive McqPolicy = iota
+	McqPolicyAlways
+	McqPolicyTyped
+)
+
+// ToString returns McqPolicy value as a string
+func (s McqPolicy) ToString() string {
+	switch s {
+	case McqPolicyProgressive:
+		return "MCQ Progressive"
+	case McqPolicyAlways:
+		return "MCQ Always"
+	case McqPolicyTyped:
+		return "MCQ Typed"
+	default:
+		return utils.UNKNOWN
+	}
+}
+
 // NotValidate performs validation of the deck
 func (deck *Deck) NotValidate() bool {
 	return len(deck.DeckName) <= utils.MinDeckNameLen || len(deck.DeckName) > utils.MaxDeckNameLen || len(deck.Description) <= utils.MinDeckNameLen || len(
 		deck.Description) > utils.MaxDefaultLen || len(deck.Banner) > utils.MaxImageURLLen || len(deck.Key) > utils.DeckKeyLen || len(
-		deck.Lang) > utils.MaxLangLen
+		deck.Lang) > utils.MaxLangLen || deck.Scheduler < SchedulerSM2 || deck.Scheduler > SchedulerFSRS || !validSteps(
+		deck.LearnSteps) || !validSteps(deck.RelearnSteps) || deck.LeechThreshold > utils.MaxLeechThreshold || (deck.McqOptions != 0 && (deck.McqOptions < utils.MinMcqOptions || deck.McqOptions > utils.MaxMcqOptions)) || !deck.validMcqPolicy()
+}
+
+// validMcqPolicy returns if the mcq policy is usable
+// Zero values fall back to the defaults
+func (deck *Deck) validMcqPolicy() bool {
+	return deck.McqPolicy >= McqPolicyProgressive && deck.McqPolicy <= McqPolicyTyped && deck.McqRepetitions <= utils.MaxMcqRepetitions && deck.McqEfactor >= 0 && deck.McqEfactor <= utils.MaxMcqEfactor && deck.McqHardEfactor >= 0 && deck.GetMcqHardEfactor() <= deck.GetMcqEfactor() && (deck.McqSuccess == 0 || (deck.McqSuccess >= MemQualityError && deck.McqSuccess <= MemQualityPerfect)) && (deck.McqFail == 0 || (deck.McqFail >= MemQualityBlackout && deck.McqFail < MemQualityError))
+}
+
+// GetMcqOptions returns the number of options shown by each mcq question
+func (deck *Deck) GetMcqOptions() int {
+	if deck.McqOptions == 0 {
+		return utils.DefaultMcqOptions
+	}
+	return int(deck.McqOptions)
+}
+
+// GetMcqEfactor returns the efactor at or below which a card needs one more review before typed input
+func (deck *Deck) GetMcqEfactor() float32 {
+	if deck.McqEfactor == 0 {
+		return utils.DefaultMcqEfactor
+	}
+	return deck.McqEfactor
+}
+
+// GetMcqHardEfactor returns the efactor at or below which a card stays MCQ
+func (deck *Deck) GetMcqHardEfactor() float32 {
+	if deck.McqHardEfactor == 0 {
+		return utils.DefaultMcqHardEfactor
+	}
+	return deck.McqHardEfactor
+}
+
+// GetMcqSuccess returns the Quality of a passed MCQ
+func (deck *Deck) GetMcqSuccess() MemQuality {
+	if deck.McqSuccess == 0 {
+		return MemQualityError
+	}
+	return deck.McqSuccess
+}
+
+// GetMcqFail returns the Quality of a failed MCQ out of learning
+func (deck *Deck) GetMcqFail() MemQuality {
+	if deck.McqFail == 0 {
+		return MemQualityErrorMCQ
+	}
+	return deck.McqFail
+}
+
+// GetLearnSteps returns the deck learning steps for new cards
+func (deck *Deck) GetLearnSteps() []time.Duration {
+	steps, _ := parseSteps(deck.LearnSteps)
+	return steps
+}
+
+// GetRelearnSteps returns the deck relearning steps for lapsed cards
+func (deck *Deck) GetRelearnSteps() []time.Duration {
+	steps, _ := parseSteps(deck.RelearnSteps)
+	return steps
+}
+
+// parseSteps parses space separated durations such as "1m 10m 1h"
+func parseSteps(steps string) ([]time.Duration, error) {
+	fields := strings.Fields(steps)
+	result := make([]time.Duration, 0, len(fields))
+
+	for i := range fields {
+		step, err := time.ParseDuration(fields[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, step)
+	}
+
+	return result, nil
+}
+
+// validSteps checks the steps format and bounds
+func validSteps(steps string) bool {
+	if len(steps) > utils.MaxStepsLen {
+		return false
+	}
+
+	parsed, err := parseSteps(steps)
+	if err != nil || len(parsed) > utils.MaxSteps {
+		return false
+	}
+
+	for i := range parsed {
+		if parsed[i] < time.Minute || parsed[i] > utils.MaxStepDuration {
+			return false
+		}
+	}
+
+	return true
 }
 
 // GenerateCode creates a random code from the deck key
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"time"
+
+	"github.com/memnix/memnixrest/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// Exam structure
+// An Exam is a scored attempt on random cards of a deck
+type Exam struct {
+	gorm.Model  `swaggerignore:"true"`
+	UserID      uint           `json:"user_id" example:"1"`
+	User        User           `swaggerignore:"true" json:"-"`
+	DeckID      uint           `json:"deck_id" example:"1"`
+	Deck        Deck           `swaggerignore:"true" json:"-"`
+	Questions   []ExamQuestion `json:"exam_questions" gorm:"foreignKey:ExamID"`
+	TimeLimit   uint           `json:"exam_time_limit" example:"600"`                      // Seconds, 0: no limit
+	Schedule    bool           `json:"exam_schedule" example:"false" gorm:"default:false"` // Answers are reviews which update the cards scheduling
+	Status      ExamStatus     `json:"exam_status" example:"1"`                            // 1: Started - 2: Submitted
+	SubmittedAt time.Time      `json:"exam_submitted_at" example:"01/01/2000"`
+	Duration    uint           `json:"exam_duration" example:"420"` // Seconds between the start and the submission
+	Late        bool           `json:"exam_late" example:"false"`   // Submitted after the time limit, every answer scores 0
+	Correct     uint           `json:"exam_correct" example:"8"`
+	Total       uint           `json:"exam_total" example:"10"`
+	Score       float64        `json:"exam_score" example:"0.85"` // Share of the points, partially correct answers count
+}
+
+// ExamQuestion structure
+type ExamQuestion struct {
+	gorm.Model `swaggerignore:"true"`
+	ExamID     uint         `json:"exam_id" example:"1"`
+	CardID     uint         `json:"card_id" example:"1"`
+	Card       Card         `swaggerignore:"true" json:"-"`
+	Cloze      uint         `json:"cloze" example:"0"`
+	Type       CardType     `json:"question_type" example:"0"` // CardMCQ when asked as a mcq
+	Question   string       `json:"question" example:"What's the answer to life ?"`
+	Format     string       `json:"question_format" example:"Date / Name / Country"`
+	Image      string       `json:"question_image"`
+	Options    []ExamOption `json:"question_options" gorm:"serializer:json"`
+	Response   string       `json:"question_response" example:"42"`
+	Validate   bool         `json:"question_validate" example:"true"`
+	Almost     bool         `json:"question_almost" example:"false"`
+	Score      float64      `json:"question_score" example:"1"`
+	Answer     string       `json:"question_answer,omitempty" example:"42"` // Set once the exam is submitted
+}
+
+// ExamOption structure
+// An ExamOption only keeps what is shown of a McqOption, so nothing tells the correct one apart
+type ExamOption struct {
+	Answer string `json:"option_answer" example:"42"`
+	Image  string `json:"option_image"` // Should be an url
+}
+
+// ExamStatus enum type
+type ExamStatus uint8
+
+const (
+	ExamStarted ExamStatus = iota + 1
+	ExamSubmitted
+)
+
+// ToString returns ExamStatus value as a string
+func (s ExamStatus) ToString() string {
+	switch s {
+	case ExamStarted:
+		return "Exam Started"
+	case ExamSubmitted:
+		return "Exam Submitted"
+	default:
+		return utils.UNKNOWN
+	}
+}
+
+// SetValidation stores the result of a question response
+func (question *ExamQuestion) SetValidation(response string, validation *CardResponseValidation) {
+	question.Response = response
+	question.Validate = validation.Validate
+	question.Almost = validation.Almost
+
+	switch {
+	case validation.Validate:
+		question.Score = 1
+	case validation.Partial:
+		question.Score = validation.Score
+	default:
+		question.Score = 0
+	}
+}
+
+// SetOptions stores the options of a question asked as a mcq
+func (question *ExamQuestion) SetOptions(options []McqOption) {
+	question.Type = CardMCQ
+	question.Options = make([]ExamOption, len(options))
+	for i := range options {
+		question.Options[i] = ExamOption{Answer: options[i].Answer, Image: options[i].Image}
+	}
+}
+
+// IsLate returns if a submission at now exceeds the time limit and its grace delay
+func (exam *Exam) IsLate(now time.Time) bool {
+	return exam.TimeLimit != 0 && now.After(exam.CreatedAt.Add(time.Duration(exam.TimeLimit)*time.Second+utils.ExamTimeGrace))
+}
+
+// Grade computes the exam results from its questions and marks it as submitted
+// The answers of a late submission are kept but score 0
+func (exam *Exam) Grade(now time.Time) {
+	exam.Status = ExamSubmitted
+	exam.SubmittedAt = now
+	exam.Late = exam.IsLate(now)
+	if now.After(exam.CreatedAt) {
+		exam.Duration = uint(now.Sub(exam.CreatedAt).Seconds())
+	}
+
+	exam.Total = uint(len(exam.Questions))
+	exam.Correct = 0
+
+	points := 0.0
+	for i := range exam.Questions {
+		if exam.Late {
+			exam.Questions[i].Validate, exam.Questions[i].Almost, exam.Questions[i].Score = false, false, 0
+		}
+		if exam.Questions[i].Validate {
+			exam.Correct++
+		}
+		points += exam.Questions[i].Score
+	}
+
+	exam.Score = 0
+	if exam.Total != 0 {
+		exam.Score = points / float64(exam.Total)
+	}
+}
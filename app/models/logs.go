@@ -104,6 +104,7 @@ const (
 	LogCardCreated         LogEvent = "card.created"
 	LogCardDeleted         LogEvent = "card.deleted"
 	LogCardEdited          LogEvent = "card.edited"
+	LogCardLeech           LogEvent = "card.leech"
 	LogAlreadyUsedEmail    LogEvent = "register.usedEmail"
 	LogIncorrectEmail      LogEvent = "login.incorrectEmail"
 	LogIncorrectPassword   LogEvent = "login.incorrectPassword"
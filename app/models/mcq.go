@@ -4,16 +4,18 @@ import (
 	"github.com/memnix/memnixrest/pkg/database"
 	"github.com/memnix/memnixrest/pkg/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"strings"
 )
 
 type Mcq struct {
 	gorm.Model `swaggerignore:"true"`
-	Name       string  `json:"mcq_name"`
-	Answers    string  `json:"mcq_answers"`
-	Type       McqType `json:"mcq_type"`
-	DeckID     uint    `json:"deck_id" example:"1"`
-	Deck       Deck    `swaggerignore:"true" json:"-"`
+	Name       string      `json:"mcq_name"`
+	Answers    string      `json:"-" swaggerignore:"true"` // Deprecated: ";" joined answers, migrated to Options by MigrateMcqAnswers
+	Options    []McqOption `json:"mcq_options" gorm:"foreignKey:McqID"`
+	Type       McqType     `json:"mcq_type"`
+	DeckID     uint        `json:"deck_id" example:"1"`
+	Deck       Deck        `swaggerignore:"true" json:"-"`
 }
 
 type McqType int64
@@ -23,37 +25,66 @@ const (
 	McqLinked
 )
 
-// GetAnswers returns a list of answers
-func (mcq *Mcq) GetAnswers() []string {
-	answersList := mcq.ExtractAnswers()
+// GetOptions returns the list of options
+// Linked options are kept in sync when their cards are created, updated or deleted
+func (mcq *Mcq) GetOptions() []McqOption {
+	db := database.DBConn // DB Conn
 
-	if mcq.Type == McqLinked {
-		answers := mcq.QueryLinkedAnswers()
-		if len(answers) != len(answersList) {
-			mcq.UpdateLinkedAnswers()
-			return answers
-		}
+	if mcq.Options == nil {
+		_ = db.Where("mcq_options.mcq_id = ?", mcq.ID).Order("mcq_options.id").Find(&mcq.Options).Error
 	}
 
-	return answersList
+	return mcq.Options
 }
 
-// ExtractAnswers method
-func (mcq *Mcq) ExtractAnswers() []string {
-	return strings.Split(mcq.Answers, ";")
+// SetAnswers replaces the options with answers
+// An option keeps its image when its answer was already an option
+func (mcq *Mcq) SetAnswers(answers []string) {
+	images := make(map[string]string, len(mcq.Options))
+	for i := range mcq.Options {
+		images[mcq.Options[i].Answer] = mcq.Options[i].Image
+	}
+
+	mcq.Options = make([]McqOption, len(answers))
+	for i := range answers {
+		mcq.Options[i] = McqOption{McqID: mcq.ID, Answer: answers[i], Image: images[answers[i]]}
+	}
 }
 
-// SetAnswers method
-func (mcq *Mcq) SetAnswers(answers []string) {
-	mcq.Answers = strings.Join(answers, ";")
+// SaveOptions replaces the stored options of the mcq with Mcq.Options
+// Concurrent saves of the same mcq are serialised by locking its row
+func (mcq *Mcq) SaveOptions() error {
+	db := database.DBConn // DB Conn
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&Mcq{}, mcq.ID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Where("mcq_options.mcq_id = ?", mcq.ID).Delete(&McqOption{}).Error; err != nil {
+			return err
+		}
+
+		if len(mcq.Options) == 0 {
+			return nil
+		}
+
+		for i := range mcq.Options {
+			mcq.Options[i].ID = 0
+			mcq.Options[i].McqID = mcq.ID
+		}
+
+		return tx.Create(&mcq.Options).Error
+	})
 }
 
 // QueryLinkedAnswers returns linked answers
+// CardCloze answers are in their question, so they are never linked
 func (mcq *Mcq) QueryLinkedAnswers() []string {
 	db := database.DBConn // DB Conn
 	var cards []Card
 
-	if err := db.Joins("Mcq").Where("cards.mcq_id = ?", mcq.ID).Find(&cards).Error; err != nil {
+	if err := db.Joins("Mcq").Where("cards.mcq_id = ? AND cards.type <> ?", mcq.ID, CardCloze).Find(&cards).Error; err != nil {
 		return make([]string, 0)
 		//TODO: Error logging
 	}
@@ -68,7 +99,25 @@ func (mcq *Mcq) QueryLinkedAnswers() []string {
 
 // NotValidate performs validation of the mcq
 func (mcq *Mcq) NotValidate() bool {
-	return mcq.Type == McqStandalone && (len(mcq.Answers) < utils.MinMcqAnswersLen || len(mcq.Answers) > utils.MaxMcqAnswersLen) || len(mcq.Name) > utils.MaxMcqName || mcq.Name == ""
+	if len(mcq.Name) > utils.MaxMcqName || mcq.Name == "" {
+		return true
+	}
+
+	if mcq.Type != McqStandalone {
+		return false
+	}
+
+	if len(mcq.Options) < utils.MinMcqOptions || len(mcq.Options) > utils.MaxMcqPool {
+		return true
+	}
+
+	for i := range mcq.Options {
+		if mcq.Options[i].NotValidate() {
+			return true
+		}
+	}
+
+	return false
 }
 
 // FillWithLinkedAnswers method
@@ -88,7 +137,6 @@ func (mcq *Mcq) FillWithLinkedAnswers() *ResponseHTTP {
 
 // UpdateLinkedAnswers method to update the db
 func (mcq *Mcq) UpdateLinkedAnswers() *ResponseHTTP {
-	db := database.DBConn // DB Conn
 	res := new(ResponseHTTP)
 
 	if err := mcq.FillWithLinkedAnswers(); !err.Success {
@@ -96,7 +144,7 @@ func (mcq *Mcq) UpdateLinkedAnswers() *ResponseHTTP {
 		return res
 	}
 
-	if err := db.Save(mcq).Error; err != nil {
+	if err := mcq.SaveOptions(); err != nil {
 		res.GenerateError(err.Error())
 		return res
 	}
@@ -104,3 +152,34 @@ func (mcq *Mcq) UpdateLinkedAnswers() *ResponseHTTP {
 	res.GenerateSuccess("Success update mcq with linked answers", nil, 0)
 	return res
 }
+
+// MigrateMcqAnswers moves the legacy ";" joined Mcq.Answers into McqOption rows
+func MigrateMcqAnswers() error {
+	db := database.DBConn // DB Conn
+
+	var mcqs []Mcq
+
+	if err := db.Where("mcqs.answers IS NOT NULL AND mcqs.answers <> ''").Find(&mcqs).Error; err != nil {
+		return err
+	}
+
+	for i := range mcqs {
+		answers := make([]string, 0)
+		for _, answer := range strings.Split(mcqs[i].Answers, ";") {
+			if answer = strings.TrimSpace(answer); answer != "" {
+				answers = append(answers, answer)
+			}
+		}
+
+		mcqs[i].SetAnswers(answers)
+		if err := mcqs[i].SaveOptions(); err != nil {
+			return err
+		}
+
+		if err := db.Model(&mcqs[i]).Update("answers", "").Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"github.com/memnix/memnixrest/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// McqOption structure
+type McqOption struct {
+	gorm.Model `swaggerignore:"true"`
+	McqID      uint   `json:"mcq_id" example:"1"`
+	Answer     string `json:"option_answer" example:"42"`
+	Image      string `json:"option_image"` // Should be an url
+}
+
+// NotValidate performs validation of the mcq option
+func (option *McqOption) NotValidate() bool {
+	return option.Answer == "" || len(option.Answer) > utils.MaxDefaultLen || len(option.Image) > utils.MaxImageURLLen
+}
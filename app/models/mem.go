@@ -1,6 +1,9 @@
 package models
 
 import (
+	"time"
+
+	"github.com/memnix/memnixrest/pkg/utils"
 	"gorm.io/gorm"
 )
 
@@ -11,11 +14,21 @@ type Mem struct {
 	User          User          `swaggerignore:"true"`
 	CardID        uint          `json:"card_id" example:"1"`
 	Card          Card          `swaggerignore:"true"`
+	Cloze         uint          `json:"cloze" example:"0" gorm:"default:0"` // CardCloze item, 0 for other cards
 	Quality       MemQuality    `json:"quality" example:"0"`
 	Repetition    uint          `json:"repetition" example:"0" `
 	Efactor       float32       `json:"e_factor" example:"2.5"`
 	Interval      uint          `json:"interval" example:"0"`
 	LearningStage LearningStage `json:"learning_stage"`
+	Stability     float32       `json:"stability" example:"0"`
+	Difficulty    float32       `json:"difficulty" example:"0"`
+	Step          uint          `json:"step" example:"0"`                              // Current learning step
+	Training      bool          `json:"training" example:"false" gorm:"default:false"` // Quality was set by a training answer
+	Response      string        `json:"-" swaggerignore:"true"`                        // Typed response which set the Quality
+	PreviousDate  time.Time     `json:"-" swaggerignore:"true"`                        // MemDate.NextDate before the review, used to undo it
+	PreviousStage LearningStage `json:"-" swaggerignore:"true"`                        // MemDate.LearningStage before the review, used to undo it
+	Reset         bool          `json:"-" swaggerignore:"true" gorm:"default:false"`   // Mem created by a progress reset, reviews before it can't be undone
+	Leeched       bool          `json:"-" swaggerignore:"true" gorm:"default:false"`   // The review made the MemDate a leech, used to undo it
 }
 
 // MemQuality enum type
@@ -31,96 +44,106 @@ const (
 	MemQualityPerfect
 )
 
-// FillDefaultValues to fill a Mem with default values for given UserID and CardID
-func (mem *Mem) FillDefaultValues(userID, cardID uint) {
+// IsSuccess returns if the Quality is a passed review
+func (mem *Mem) IsSuccess() bool {
+	return mem.Quality >= MemQualityError
+}
+
+// IsLapse returns if the Quality is a failed review of a graduated card
+func (mem *Mem) IsLapse() bool {
+	return !mem.Training && mem.Quality != MemQualityNone && !mem.IsSuccess() && mem.LearningStage > StageToRelearn
+}
+
+// IsOverridable returns if mem holds the verdict of a rejected typed response which can still be accepted
+// next is the Mem created by that review
+func (mem *Mem) IsOverridable(next *Mem) bool {
+	return !next.Reset && !next.PreviousDate.IsZero() && mem.Response != "" && !mem.IsSuccess()
+}
+
+// SetPrevious saves the MemDate state before the review so it can be undone
+func (mem *Mem) SetPrevious(memDate *MemDate) {
+	mem.PreviousDate = memDate.NextDate
+	mem.PreviousStage = memDate.LearningStage
+}
+
+// FillDefaultValues to fill a Mem with default values for given UserID, CardID and Cloze
+func (mem *Mem) FillDefaultValues(userID, cardID, cloze uint) {
 	mem.UserID = userID
 	mem.CardID = cardID
+	mem.Cloze = cloze
 	mem.Quality = MemQualityBlackout
 	mem.Repetition = 0
 	mem.Efactor = 2.5
 	mem.Interval = 0
 	mem.LearningStage = StageToLearn
-}
-
-// ComputeEfactor calculates and sets new efactor using oldEfactor and MemQuality
-func (mem *Mem) ComputeEfactor(oldEfactor float32, quality MemQuality) {
-	eFactor := oldEfactor + (0.1 - (5.0-float32(quality))*(0.08+(5-float32(quality)))*0.02)
-
-	if eFactor < 1.3 {
-		mem.Efactor = 1.3
-	} else {
-		mem.Efactor = eFactor
-	}
-}
-
-// ComputeTrainingEfactor calculates and sets new efactor using oldEfactor and MemQuality
-// TrainingEfactor is a median between oldEfactor and ComputeEfactor
-func (mem *Mem) ComputeTrainingEfactor(oldEfactor float32, quality MemQuality) {
-	mem.ComputeEfactor(oldEfactor, quality)
-	computedTrainingEfactor := (oldEfactor + mem.Efactor) / 2
-	if computedTrainingEfactor < 1.3 {
-		mem.Efactor = 1.3
-	} else {
-		mem.Efactor = computedTrainingEfactor
-	}
+	mem.Stability = 0
+	mem.Difficulty = 0
+	mem.Step = 0
 }
 
 // GetCardType returns the current CardType
-// The CardType is CardMCQ if internal conditions are matched.
+// The CardType is CardMCQ if the deck mcq policy is matched.
 // Otherwise, it's Card.Type
-func (mem *Mem) GetCardType() CardType {
-	if mem.IsMCQ() {
+func (mem *Mem) GetCardType(deck *Deck) CardType {
+	if mem.IsMCQ(deck) {
 		return CardMCQ
 	}
 
 	return mem.Card.Type
 }
 
-// ComputeInterval calculates and sets the interval between reviews
-func (mem *Mem) ComputeInterval(oldInterval uint, eFactor float32, repetition uint) {
-	switch repetition {
-	case 0:
-		mem.Interval = 1
-	case 1, 2:
-		mem.Interval = 2
-	case 3:
-		mem.Interval = 3
+// ComputeQualitySuccess sets the answer Quality
+func (mem *Mem) ComputeQualitySuccess(deck *Deck) {
+	switch {
+	case mem.GetCardType(deck) == CardMCQ || mem.LearningStage == StageToLearn:
+		mem.Quality = deck.GetMcqSuccess()
+	case mem.LearningStage == StageKnown:
+		mem.Quality = MemQualityPerfect
 	default:
-		mem.Interval = uint(float32(oldInterval)*eFactor*0.75) + 1
+		mem.Quality = MemQualityGoodMCQ
 	}
 }
 
-func (mem *Mem) ComputeLearningStage() {
+// ComputeQualityAlmost sets the answer Quality of a near-miss
+func (mem *Mem) ComputeQualityAlmost() {
+	mem.Quality = MemQualityError
+}
+
+// ComputeQualitySelfEvaluated sets the Quality of a self evaluation grade, from Again (1) to Easy (4)
+// Again is a failed review and the other grades are passed reviews
+func (mem *Mem) ComputeQualitySelfEvaluated(grade uint) {
 	switch {
-	case mem.Repetition > 3 && mem.Repetition < 7:
-		mem.LearningStage = StageReviewing
-	case mem.Repetition > 7:
-		mem.LearningStage = StageKnown
+	case grade <= 1:
+		mem.Quality = MemQualityBlackout
+	case grade == 2:
+		mem.Quality = MemQualityError
+	case grade == 3:
+		mem.Quality = MemQualityGoodMCQ
 	default:
-		mem.LearningStage = StageLearning
+		mem.Quality = MemQualityPerfect
 	}
 }
 
-// ComputeQualitySuccess sets the answer Quality
-func (mem *Mem) ComputeQualitySuccess() {
+// ComputeQualityPartial sets the answer Quality of a partially correct multi-field answer
+func (mem *Mem) ComputeQualityPartial(score float64) {
 	switch {
-	case mem.GetCardType() == CardMCQ || mem.LearningStage == StageToLearn:
-		mem.Quality = MemQualityError
-	case mem.LearningStage == StageKnown:
-		mem.Quality = MemQualityPerfect
+	case score >= 2.0/3:
+		mem.Quality = MemQualityErrorHints
+	case score >= 1.0/3:
+		mem.Quality = MemQualityErrorMCQ
 	default:
-		mem.Quality = MemQualityGoodMCQ
+		mem.Quality = MemQualityBlackout
 	}
 }
 
 // ComputeQualityFail sets the answer Quality
-func (mem *Mem) ComputeQualityFail() {
+func (mem *Mem) ComputeQualityFail(deck *Deck) {
 	switch {
-	case mem.GetCardType() == CardMCQ:
+	case mem.GetCardType(deck) == CardMCQ:
 		if mem.LearningStage == StageToLearn {
 			mem.Quality = MemQualityBlackout
 		} else {
-			mem.Quality = MemQualityErrorMCQ
+			mem.Quality = deck.GetMcqFail()
 		}
 	case mem.LearningStage == StageLearning:
 		mem.Quality = MemQualityErrorMCQ
@@ -129,8 +152,20 @@ func (mem *Mem) ComputeQualityFail() {
 	}
 }
 
-// IsMCQ returns if the Mem should be an MCQ or not.
+// IsMCQ returns if the Mem should be an MCQ or not according to the deck mcq policy.
 // It doesn't include Card.Type checks
-func (mem *Mem) IsMCQ() bool {
-	return mem.LearningStage < StageReviewing || mem.Efactor <= 1.7 || mem.Repetition < 2 || (mem.Efactor <= 2.3 && mem.Repetition < 3)
+func (mem *Mem) IsMCQ(deck *Deck) bool {
+	switch deck.McqPolicy {
+	case McqPolicyAlways:
+		return true
+	case McqPolicyTyped:
+		return false
+	}
+
+	return mem.LearningStage < StageReviewing || mem.Efactor <= deck.GetMcqHardEfactor() || mem.Repetition < deck.McqRepetitions || (mem.Efactor <= deck.GetMcqEfactor() && mem.Repetition < deck.McqRepetitions+1)
+}
+
+// IsExperienced returns if the card has been passed several times in review
+func (mem *Mem) IsExperienced() bool {
+	return mem.LearningStage >= StageReviewing && mem.Repetition >= utils.ExperiencedRepetitions
 }
@@ -14,10 +14,15 @@ type MemDate struct {
 	User          User          `swaggerignore:"true"`
 	CardID        uint          `json:"card_id" example:"1"`
 	Card          Card          `swaggerignore:"true"`
+	Cloze         uint          `json:"cloze" example:"0" gorm:"default:0"` // CardCloze item, 0 for other cards
 	DeckID        uint          `json:"deck_id" example:"1"`
 	Deck          Deck          `swaggerignore:"true"`
 	NextDate      time.Time     `json:"next_date" example:"01/01/2000"` // gorm:"autoCreateTime"`
 	LearningStage LearningStage `json:"learning_stage" gorm:"default:0"`
+	Suspended     bool          `json:"suspended" gorm:"default:false"`
+	BuriedUntil   time.Time     `json:"buried_until" example:"01/01/2000"`
+	Lapses        uint          `json:"lapses" example:"0" gorm:"default:0"`
+	Leech         bool          `json:"leech" example:"false" gorm:"default:false"`
 }
 
 // LearningStage enum type
@@ -32,17 +37,93 @@ const (
 	StageKnown
 )
 
+// IsStep returns if the stage is a learning or relearning stage, whose intraday steps don't count in the daily limits
+func (stage LearningStage) IsStep() bool {
+	return stage == StageToLearn || stage == StageToRelearn
+}
+
 // ComputeNextDate calculates and sets the NextDate
+// The NextDate is the start of the MemDate.User day, interval days after today
 func (m *MemDate) ComputeNextDate(interval int) {
-	m.NextDate = time.Now().AddDate(0, 0, interval)
+	m.NextDate = m.User.GetDayStart(time.Now()).AddDate(0, 0, interval)
+}
+
+// ComputeNextStep sets the NextDate after a learning step delay
+func (m *MemDate) ComputeNextStep(step time.Duration) {
+	m.NextDate = time.Now().Add(step)
+}
+
+// Suspend sets the MemDate suspended state
+func (m *MemDate) Suspend(suspended bool) {
+	m.Suspended = suspended
 }
 
-// SetDefaultNextDate fills MemDate values and sets NextDate as time.Now()
-func (m *MemDate) SetDefaultNextDate(userID, cardID, deckID uint) {
-	m.UserID = userID
+// Bury hides the MemDate until the next user day
+func (m *MemDate) Bury(user *User) {
+	m.BuriedUntil = user.GetTodayEnd()
+}
+
+// Reset sets the MemDate back to a never seen card
+func (m *MemDate) Reset(user *User) {
+	m.LearningStage = StageNeverSeen
+	m.NextDate = user.GetDayStart(time.Now())
+	m.Suspended = false
+	m.BuriedUntil = time.Time{}
+	m.Lapses = 0
+	m.Leech = false
+}
+
+// AddLapse counts a lapse and tags the MemDate as a leech once Deck.LeechThreshold is reached
+// It returns true if the MemDate just became a leech
+func (m *MemDate) AddLapse(deck *Deck) bool {
+	m.Lapses++
+
+	if m.Leech || deck.LeechThreshold == 0 || m.Lapses < deck.LeechThreshold {
+		return false
+	}
+
+	m.Leech = true
+	if deck.LeechSuspend {
+		m.Suspended = true
+	}
+
+	return true
+}
+
+// RemoveLapse uncounts an undone lapse
+// If that lapse made the MemDate a leech, the leech tag and its auto suspension are removed too
+func (m *MemDate) RemoveLapse(deck *Deck, leeched bool) {
+	if m.Lapses > 0 {
+		m.Lapses--
+	}
+
+	if !leeched {
+		return
+	}
+
+	m.Leech = false
+	if deck.LeechSuspend {
+		m.Suspended = false
+	}
+}
+
+// Restore sets the MemDate back to its state before the review which created mem, last holding its verdict
+func (m *MemDate) Restore(mem, last *Mem) {
+	m.NextDate = mem.PreviousDate
+	m.LearningStage = mem.PreviousStage
+
+	if last.IsLapse() {
+		m.RemoveLapse(&m.Deck, last.Leeched)
+	}
+}
+
+// SetDefaultNextDate fills MemDate values and sets NextDate as the start of the user day
+func (m *MemDate) SetDefaultNextDate(user *User, cardID, deckID, cloze uint) {
+	m.UserID = user.ID
 	m.CardID = cardID
+	m.Cloze = cloze
 	m.DeckID = deckID
-	m.NextDate = time.Now()
+	m.NextDate = user.GetDayStart(time.Now())
 }
 
 // GetNextToday fills MemDate with the next today card to review for a given user
@@ -51,13 +132,19 @@ func (m *MemDate) GetNextToday(userID uint) *ResponseHTTP {
 	db := database.DBConn // DB Conn
 	res := new(ResponseHTTP)
 
-	// Get next card with date condition
-	t := time.Now()
+	user := new(User)
+	if err := db.First(&user, userID).Error; err != nil {
+		res.GenerateError("Next today memDate not found")
+		return res
+	}
+
+	now := time.Now()
+
+	// Get next card with date condition, cards in a learning step wait for their step delay
 	if err := db.Joins(
 		"left join accesses ON mem_dates.deck_id = accesses.deck_id AND accesses.user_id = ?",
-		userID).Joins("Card").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.next_date < ? AND accesses.permission >= ?",
-		userID, t.AddDate(0, 0, 1).Add(
-			time.Duration(-t.Hour())*time.Hour), AccessStudent).Limit(1).Order("next_date asc").Find(&m).Error; err != nil {
+		userID).Joins("Card").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.next_date < ? AND (mem_dates.learning_stage NOT IN ? OR mem_dates.next_date <= ?) AND accesses.permission >= ? AND mem_dates.suspended IS false AND (mem_dates.buried_until IS NULL OR mem_dates.buried_until <= ?)",
+		userID, user.GetTodayEnd(), []LearningStage{StageToLearn, StageToRelearn}, now, AccessStudent, now).Limit(1).Order("next_date asc").Find(&m).Error; err != nil {
 		res.GenerateError("Next today memDate not found")
 		return res
 	}
@@ -76,8 +163,8 @@ func (m *MemDate) GetNext(userID uint) *ResponseHTTP {
 	// Get next card
 	if err := db.Joins(
 		"left join accesses ON mem_dates.deck_id = accesses.deck_id AND accesses.user_id = ?",
-		userID).Joins("Card").Joins("Deck").Where("mem_dates.user_id = ? AND accesses.permission >= ?",
-		userID, AccessStudent).Limit(1).Order("next_date asc").Find(&m).Error; err != nil {
+		userID).Joins("Card").Joins("Deck").Where("mem_dates.user_id = ? AND accesses.permission >= ? AND mem_dates.suspended IS false AND (mem_dates.buried_until IS NULL OR mem_dates.buried_until <= ?)",
+		userID, AccessStudent, time.Now()).Limit(1).Order("next_date asc").Find(&m).Error; err != nil {
 		res.GenerateError("Next memDate not found")
 		return res
 	}
@@ -94,8 +181,8 @@ func (m *MemDate) GetNextByDeck(userID, deckID uint) *ResponseHTTP {
 	res := new(ResponseHTTP)
 
 	// Get next card  with deck condition
-	if err := db.Joins("Card").Joins("User").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.deck_id = ?",
-		userID, deckID).Limit(1).Order("next_date asc").Find(&m).Error; err != nil {
+	if err := db.Joins("Card").Joins("User").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.deck_id = ? AND mem_dates.suspended IS false AND (mem_dates.buried_until IS NULL OR mem_dates.buried_until <= ?)",
+		userID, deckID, time.Now()).Limit(1).Order("next_date asc").Find(&m).Error; err != nil {
 		res.GenerateError("Next memDate by deck not found")
 		return res
 	}
@@ -104,3 +191,34 @@ func (m *MemDate) GetNextByDeck(userID, deckID uint) *ResponseHTTP {
 
 	return res
 }
+
+// MigrateLearningStages backfills the LearningStage of the MemDates reviewed before it was stored, from their latest Mem
+// A MemDate is only backfilled if it has a review which wasn't followed by a reset
+func MigrateLearningStages() error {
+	db := database.DBConn // DB Conn
+
+	return db.Exec("UPDATE mem_dates SET learning_stage = GREATEST(latest.learning_stage, ?) FROM ("+
+		"SELECT DISTINCT ON (mems.user_id, mems.card_id, mems.cloze) mems.user_id, mems.card_id, mems.cloze, mems.learning_stage, mems.reset "+
+		"FROM mems WHERE mems.deleted_at IS NULL ORDER BY mems.user_id, mems.card_id, mems.cloze, mems.id DESC) AS latest "+
+		"WHERE mem_dates.learning_stage = ? AND mem_dates.deleted_at IS NULL AND mem_dates.user_id = latest.user_id AND mem_dates.card_id = latest.card_id "+
+		"AND mem_dates.cloze = latest.cloze AND latest.reset IS false AND EXISTS (SELECT 1 FROM mems reviewed WHERE reviewed.user_id = latest.user_id "+
+		"AND reviewed.card_id = latest.card_id AND reviewed.cloze = latest.cloze AND reviewed.deleted_at IS NULL AND reviewed.training IS false "+
+		"AND reviewed.quality <> ? AND NOT EXISTS (SELECT 1 FROM mems reset WHERE reset.user_id = reviewed.user_id AND reset.card_id = reviewed.card_id "+
+		"AND reset.cloze = reviewed.cloze AND reset.deleted_at IS NULL AND reset.reset IS true AND reset.id > reviewed.id))",
+		StageToLearn, StageNeverSeen, MemQualityNone).Error
+}
+
+// MigrateLapses backfills the Lapses of the MemDates from their Mem history since their last reset
+// MemDates reaching Deck.LeechThreshold are tagged as leeches but not suspended
+func MigrateLapses() error {
+	db := database.DBConn // DB Conn
+
+	return db.Exec("UPDATE mem_dates SET lapses = counted.lapses, leech = (decks.leech_threshold > 0 AND counted.lapses >= decks.leech_threshold) FROM ("+
+		"SELECT mems.user_id, mems.card_id, mems.cloze, count(*) AS lapses FROM mems WHERE mems.deleted_at IS NULL AND mems.training IS false "+
+		"AND mems.quality <> ? AND mems.quality < ? AND mems.learning_stage > ? AND NOT EXISTS (SELECT 1 FROM mems reset WHERE reset.user_id = mems.user_id "+
+		"AND reset.card_id = mems.card_id AND reset.cloze = mems.cloze AND reset.deleted_at IS NULL AND reset.reset IS true AND reset.id > mems.id) "+
+		"GROUP BY mems.user_id, mems.card_id, mems.cloze) AS counted, decks "+
+		"WHERE mem_dates.lapses = 0 AND mem_dates.deleted_at IS NULL AND mem_dates.user_id = counted.user_id AND mem_dates.card_id = counted.card_id "+
+		"AND mem_dates.cloze = counted.cloze AND decks.id = mem_dates.deck_id",
+		MemQualityNone, MemQualityError, StageToRelearn).Error
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Override structure
+// An Override records a rejected response the user marked as correct
+type Override struct {
+	gorm.Model `swaggerignore:"true"`
+	UserID     uint   `json:"user_id" example:"1"`
+	User       User   `swaggerignore:"true" json:"-"`
+	CardID     uint   `json:"card_id" example:"1"`
+	Card       Card   `swaggerignore:"true" json:"-"`
+	DeckID     uint   `json:"deck_id" example:"1"`
+	Cloze      uint   `json:"cloze" example:"0"`
+	Response   string `json:"response" example:"Tchaikovski"`
+}
@@ -1,7 +1,9 @@
 package models
 
 import (
+	"github.com/memnix/memnixrest/pkg/utils"
 	"gorm.io/gorm"
+	"time"
 )
 
 // Answer structure
@@ -12,15 +14,116 @@ type Answer struct {
 	Answer string `json:"answer" example:"42"`
 }
 
+// NotValidate performs validation of the answer
+func (answer *Answer) NotValidate() bool {
+	return answer.Answer == "" || len(answer.Answer) > utils.MaxDefaultLen
+}
+
 // DeckResponse structure
 type DeckResponse struct {
-	DeckID uint           `json:"deck_id"`
-	Deck   Deck           `json:"deck"`
-	Cards  []ResponseCard `json:"cards"`
-	Count  int            `json:"count"`
+	DeckID   uint           `json:"deck_id"`
+	Deck     Deck           `json:"deck"`
+	Cards    []ResponseCard `json:"cards"`
+	Count    int            `json:"count"`
+	HeldBack int            `json:"held_back"` // Cards held back by the daily limits
 }
 
 type TodayResponse struct {
 	DecksReponses []DeckResponse `json:"decks_responses"`
 	Count         int            `json:"count"`
+	HeldBack      int            `json:"held_back"`
+}
+
+// LeechReport structure
+type LeechReport struct {
+	CardID      uint    `json:"card_id" example:"1"`
+	Card        Card    `json:"card"`
+	Subscribers int     `json:"subscribers" example:"10"`
+	Leeches     int     `json:"leeches" example:"2"`
+	Lapses      int     `json:"lapses" example:"17"`
+	LeechRate   float64 `json:"leech_rate" example:"0.2"`
+}
+
+// OverrideReport structure
+type OverrideReport struct {
+	CardID   uint   `json:"card_id" example:"1"`
+	Card     Card   `json:"card"`
+	Cloze    uint   `json:"cloze" example:"0"`
+	Response string `json:"response" example:"Tchaikovski"`
+	Count    int    `json:"count" example:"4"`
+}
+
+// IntervalPreview structure
+type IntervalPreview struct {
+	Quality  MemQuality `json:"quality" example:"3"`            // Self evaluation grade
+	Interval uint       `json:"interval" example:"6"`           // Days before the next review, 0 in a learning step
+	NextDate time.Time  `json:"next_date" example:"01/01/2000"` // Next review date
+}
+
+// SessionResponse structure
+type SessionResponse struct {
+	Session   Session       `json:"session"`
+	Card      *ResponseCard `json:"card"` // Card to review, nil once the queue is empty
+	Remaining int           `json:"remaining" example:"12"`
+}
+
+// SessionSummary structure
+type SessionSummary struct {
+	SessionID   uint    `json:"session_id" example:"1"`
+	DeckID      uint    `json:"deck_id" example:"1"`
+	Training    bool    `json:"training" example:"false"`
+	Finished    bool    `json:"finished" example:"true"`
+	Total       uint    `json:"total" example:"20"`
+	Remaining   uint    `json:"remaining" example:"0"`
+	Reviewed    uint    `json:"reviewed" example:"24"`
+	Passed      uint    `json:"passed" example:"20"`
+	Failed      uint    `json:"failed" example:"4"`
+	Requeued    uint    `json:"requeued" example:"4"`
+	Accuracy    float64 `json:"accuracy" example:"0.83"`
+	Elapsed     uint    `json:"elapsed" example:"300"`       // Active seconds
+	AverageTime float64 `json:"average_time" example:"12.5"` // Active seconds per answer
+}
+
+// ExamResult structure
+type ExamResult struct {
+	ExamID      uint       `json:"exam_id" example:"1"`
+	User        PublicUser `json:"user"`
+	Score       float64    `json:"exam_score" example:"0.85"`
+	Correct     uint       `json:"exam_correct" example:"8"`
+	Total       uint       `json:"exam_total" example:"10"`
+	Duration    uint       `json:"exam_duration" example:"420"`
+	Late        bool       `json:"exam_late" example:"false"`
+	SubmittedAt time.Time  `json:"exam_submitted_at" example:"01/01/2000"`
+}
+
+// ForecastDeck structure
+type ForecastDeck struct {
+	DeckID uint                  `json:"deck_id" example:"1"`
+	Count  int                   `json:"count" example:"12"`
+	Stages map[LearningStage]int `json:"stages"`
+}
+
+// ForecastDay structure
+type ForecastDay struct {
+	Date   time.Time             `json:"date" example:"01/01/2000"`
+	Count  int                   `json:"count" example:"42"`
+	Stages map[LearningStage]int `json:"stages"`
+	Decks  []ForecastDeck        `json:"decks"`
+}
+
+// DayStatistics structure
+type DayStatistics struct {
+	Date    time.Time `json:"date" example:"01/01/2000"`
+	Reviews int       `json:"reviews" example:"42"`
+	Passed  int       `json:"passed" example:"38"`
+}
+
+// Statistics structure
+type Statistics struct {
+	Reviews       int                   `json:"reviews" example:"420"`
+	Passed        int                   `json:"passed" example:"380"`
+	TrueRetention float64               `json:"true_retention" example:"0.9"` // Pass rate of cards out of learning steps
+	ReviewsPerDay []DayStatistics       `json:"reviews_per_day"`
+	Stages        map[LearningStage]int `json:"stages"`
+	Efactors      map[string]int        `json:"efactors"` // Number of cards by efactor rounded to 0.1
 }
@@ -0,0 +1,209 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/memnix/memnixrest/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// Session structure
+// A Session holds a study queue server-side so it can be resumed on any device
+type Session struct {
+	gorm.Model   `swaggerignore:"true"`
+	UserID       uint          `json:"user_id" example:"1"`
+	User         User          `swaggerignore:"true" json:"-"`
+	DeckID       uint          `json:"deck_id" example:"1"` // 0: today cards of every deck
+	Training     bool          `json:"training" example:"false" gorm:"default:false"`
+	Status       SessionStatus `json:"status" example:"1"`     // 1: Active - 2: Finished
+	Queue        string        `json:"-" swaggerignore:"true"` // Space separated MemDate IDs left to review, the current one first
+	Total        uint          `json:"total" example:"20"`     // Cards queued when the session started
+	Reviewed     uint          `json:"reviewed" example:"24"`  // Answers given, requeued cards included
+	Passed       uint          `json:"passed" example:"20"`
+	Failed       uint          `json:"failed" example:"4"`
+	Requeued     uint          `json:"requeued" example:"4"`
+	Elapsed      uint          `json:"elapsed" example:"300"` // Active seconds, idle gaps are capped to utils.MaxSessionIdle
+	LastActivity time.Time     `json:"last_activity" example:"01/01/2000"`
+}
+
+// SessionStatus enum type
+type SessionStatus uint8
+
+const (
+	SessionActive SessionStatus = iota + 1
+	SessionFinished
+)
+
+// ToString returns SessionStatus value as a string
+func (s SessionStatus) ToString() string {
+	switch s {
+	case SessionActive:
+		return "Session Active"
+	case SessionFinished:
+		return "Session Finished"
+	default:
+		return utils.UNKNOWN
+	}
+}
+
+// Start fills a new Session with the MemDates to review
+func (session *Session) Start(userID, deckID uint, training bool, memDates []MemDate, now time.Time) {
+	session.UserID, session.DeckID, session.Training = userID, deckID, training
+	session.Status = SessionActive
+	session.LastActivity = now
+
+	queue := make([]uint, len(memDates))
+	for i := range memDates {
+		queue[i] = memDates[i].ID
+	}
+	session.SetQueue(queue)
+	session.Total = uint(len(queue))
+
+	if len(queue) == 0 {
+		session.Status = SessionFinished
+	}
+}
+
+// GetQueue returns the MemDate IDs left to review
+func (session *Session) GetQueue() []uint {
+	fields := strings.Fields(session.Queue)
+	queue := make([]uint, 0, len(fields))
+
+	for i := range fields {
+		id, err := strconv.ParseUint(fields[i], 10, 32)
+		if err != nil {
+			continue
+		}
+		queue = append(queue, uint(id))
+	}
+
+	return queue
+}
+
+// SetQueue sets the MemDate IDs left to review
+func (session *Session) SetQueue(queue []uint) {
+	fields := make([]string, len(queue))
+	for i := range queue {
+		fields[i] = strconv.FormatUint(uint64(queue[i]), 10)
+	}
+	session.Queue = strings.Join(fields, " ")
+}
+
+// Current returns the MemDate ID to review, 0 if the queue is empty
+func (session *Session) Current() uint {
+	queue := session.GetQueue()
+	if len(queue) == 0 {
+		return 0
+	}
+	return queue[0]
+}
+
+// Track adds the time spent since the last activity to Elapsed
+func (session *Session) Track(now time.Time) {
+	if !session.LastActivity.IsZero() && now.After(session.LastActivity) {
+		idle := now.Sub(session.LastActivity)
+		if idle > utils.MaxSessionIdle {
+			idle = utils.MaxSessionIdle
+		}
+		session.Elapsed += uint(idle.Seconds())
+	}
+	session.LastActivity = now
+}
+
+// Answer records an answer on a queued MemDate
+// A failed MemDate is queued again utils.SessionRequeueGap cards later
+// It returns false if the MemDate isn't in the queue
+func (session *Session) Answer(memDateID uint, passed bool, now time.Time) bool {
+	if session.Status != SessionActive {
+		return false
+	}
+
+	queue := session.GetQueue()
+
+	index := -1
+	for i := range queue {
+		if queue[i] == memDateID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return false
+	}
+
+	queue = append(queue[:index], queue[index+1:]...)
+
+	session.Track(now)
+	session.Reviewed++
+
+	if passed {
+		session.Passed++
+	} else {
+		session.Failed++
+		session.Requeued++
+
+		position := utils.SessionRequeueGap
+		if position > len(queue) {
+			position = len(queue)
+		}
+		queue = append(queue[:position], append([]uint{memDateID}, queue[position:]...)...)
+	}
+
+	session.SetQueue(queue)
+
+	if len(queue) == 0 {
+		session.Status = SessionFinished
+	}
+
+	return true
+}
+
+// Skip removes a MemDate from the queue without answering it
+func (session *Session) Skip(memDateID uint) {
+	queue := session.GetQueue()
+	for i := range queue {
+		if queue[i] == memDateID {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	session.SetQueue(queue)
+
+	if len(queue) == 0 {
+		session.Status = SessionFinished
+	}
+}
+
+// Finish ends the session
+func (session *Session) Finish(now time.Time) {
+	if session.Status == SessionActive {
+		session.Track(now)
+	}
+	session.Status = SessionFinished
+}
+
+// GetSummary returns the end-of-session summary
+func (session *Session) GetSummary() SessionSummary {
+	summary := SessionSummary{
+		SessionID: session.ID,
+		DeckID:    session.DeckID,
+		Training:  session.Training,
+		Finished:  session.Status == SessionFinished,
+		Total:     session.Total,
+		Remaining: uint(len(session.GetQueue())),
+		Reviewed:  session.Reviewed,
+		Passed:    session.Passed,
+		Failed:    session.Failed,
+		Requeued:  session.Requeued,
+		Elapsed:   session.Elapsed,
+	}
+
+	if session.Reviewed != 0 {
+		summary.Accuracy = float64(session.Passed) / float64(session.Reviewed)
+		summary.AverageTime = float64(session.Elapsed) / float64(session.Reviewed)
+	}
+
+	return summary
+}
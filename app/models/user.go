@@ -1,7 +1,9 @@
 package models
 
 import (
+	"github.com/memnix/memnixrest/pkg/utils"
 	"gorm.io/gorm"
+	"time"
 )
 
 // User structure
@@ -13,6 +15,41 @@ type User struct {
 	Bio         string     `json:"user_bio" example:"A simple demo bio"`
 	Email       string     `json:"email" gorm:"unique"`
 	Password    []byte     `json:"-" swaggerignore:"true"`
+	Timezone    string     `json:"user_timezone" example:"Europe/Paris"`        // IANA name, server timezone if empty
+	DayStart    uint8      `json:"user_day_start" example:"4" gorm:"default:0"` // Hour of the day rollover
+	LoadBalance bool       `json:"user_load_balance" example:"false" gorm:"default:false"`
+}
+
+// GetLocation returns the user time.Location
+// It falls back to the server location if the timezone is unknown
+func (user *User) GetLocation() *time.Location {
+	if user.Timezone == "" {
+		return time.Local
+	}
+
+	location, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return time.Local
+	}
+
+	return location
+}
+
+// GetDayStart returns the start of the user day containing t
+func (user *User) GetDayStart(t time.Time) time.Time {
+	local := t.In(user.GetLocation())
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), int(user.DayStart), 0, 0, 0, local.Location())
+
+	if local.Before(dayStart) {
+		return dayStart.AddDate(0, 0, -1)
+	}
+
+	return dayStart
+}
+
+// GetTodayEnd returns the end of the current user day
+func (user *User) GetTodayEnd() time.Time {
+	return user.GetDayStart(time.Now()).AddDate(0, 0, 1)
 }
 
 type LoginStruct struct {
@@ -37,6 +74,27 @@ type PasswordResetConfirm struct {
 	Pass  string `json:"password"`
 }
 
+// TimeConfig struct
+type TimeConfig struct {
+	Timezone string `json:"settings_timezone" example:"Europe/Paris"`
+	DayStart uint8  `json:"settings_day_start" example:"4"`
+}
+
+// NotValidate performs validation of the TimeConfig
+func (config *TimeConfig) NotValidate() bool {
+	if len(config.Timezone) > utils.MaxTimezoneLen || config.DayStart > utils.MaxDayStartHour {
+		return true
+	}
+
+	_, err := time.LoadLocation(config.Timezone)
+	return err != nil
+}
+
+// BalanceConfig struct
+type BalanceConfig struct {
+	LoadBalance bool `json:"settings_load_balance" example:"true"`
+}
+
 type PublicUser struct {
 	ID          uint       `json:"user_id"`
 	Username    string     `json:"user_name"`
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"github.com/memnix/memnixrest/pkg/utils"
+	"time"
+)
+
 // ResponseHTTP structure to format API answers
 type ResponseHTTP struct {
 	Success bool        `json:"success"`
@@ -33,26 +38,177 @@ type DeckConfig struct {
 	TodaySetting bool `json:"settings_today"`
 }
 
+// SessionConfig struct
+type SessionConfig struct {
+	DeckID   uint `json:"deck_id" example:"1"` // 0: today cards of every deck
+	Training bool `json:"training" example:"false"`
+}
+
+// ExamConfig struct
+type ExamConfig struct {
+	DeckID    uint `json:"deck_id" example:"1"`
+	Count     uint `json:"exam_count" example:"10"`       // Number of random cards
+	Mcq       uint `json:"exam_mcq" example:"50"`         // Percentage of questions asked as mcq, cards without mcq are always typed
+	TimeLimit uint `json:"exam_time_limit" example:"600"` // Seconds, 0: no limit
+	Schedule  bool `json:"exam_schedule" example:"false"` // Answers are reviews which update the cards scheduling
+}
+
+// NotValidate performs validation of the ExamConfig
+func (config *ExamConfig) NotValidate() bool {
+	return config.Count == 0 || config.Count > utils.MaxExamQuestions || config.Mcq > 100 || config.TimeLimit > utils.MaxExamTimeLimit
+}
+
+// ExamAnswer struct
+type ExamAnswer struct {
+	QuestionID uint     `json:"question_id" example:"1"`
+	Response   string   `json:"response" example:"42"`
+	Fields     []string `json:"fields"` // Multi-field response, Response is split on "/" if empty
+}
+
+// ExamSubmission struct
+type ExamSubmission struct {
+	Answers []ExamAnswer `json:"answers"`
+}
+
+// NotValidate performs validation of the ExamSubmission
+func (submission *ExamSubmission) NotValidate() bool {
+	if len(submission.Answers) > utils.MaxExamQuestions {
+		return true
+	}
+
+	for i := range submission.Answers {
+		if !validResponse(submission.Answers[i].Response, submission.Answers[i].Fields) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validResponse returns if a response and its fields are short enough to be checked
+func validResponse(response string, fields []string) bool {
+	if len(response) > utils.MaxDefaultLen || len(fields) > utils.MaxCardFormatLen {
+		return false
+	}
+
+	for i := range fields {
+		if len(fields[i]) > utils.MaxDefaultLen {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DeckLimitsConfig struct
+type DeckLimitsConfig struct {
+	MaxNew     uint `json:"settings_max_new" example:"20"`
+	MaxReviews uint `json:"settings_max_reviews" example:"200"`
+}
+
+// NotValidate performs validation of the DeckLimitsConfig
+func (config *DeckLimitsConfig) NotValidate() bool {
+	return config.MaxNew > utils.MaxDailyLimit || config.MaxReviews > utils.MaxDailyLimit
+}
+
+// ExamDateConfig struct
+type ExamDateConfig struct {
+	ExamDate time.Time `json:"settings_exam_date" example:"2022-06-15T00:00:00Z"` // Zero value removes the exam date
+}
+
+// NotValidate performs validation of the ExamDateConfig
+// Only the calendar day of the exam date is compared with the user day
+func (config *ExamDateConfig) NotValidate(dayStart time.Time) bool {
+	if config.ExamDate.IsZero() {
+		return false
+	}
+
+	examDay := time.Date(config.ExamDate.Year(), config.ExamDate.Month(), config.ExamDate.Day(), 0, 0, 0, 0, dayStart.Location())
+	today := time.Date(dayStart.Year(), dayStart.Month(), dayStart.Day(), 0, 0, 0, 0, dayStart.Location())
+
+	return examDay.Before(today)
+}
+
 // CardResponse struct
 type CardResponse struct {
-	CardID   uint   `json:"card_id" example:"1"`
-	Card     Card   `json:"-" swaggerignore:"true"`
-	Response string `json:"response" example:"42"`
-	Training bool   `json:"training" example:"false"`
+	CardID   uint     `json:"card_id" example:"1"`
+	Card     Card     `json:"-" swaggerignore:"true"`
+	Cloze    uint     `json:"cloze" example:"0"`
+	Response string   `json:"response" example:"42"`
+	Fields   []string `json:"fields"` // Multi-field response, Response is split on "/" if empty
+	Training bool     `json:"training" example:"false"`
+}
+
+// NotValidate performs validation of the CardResponse
+func (response *CardResponse) NotValidate() bool {
+	return !validResponse(response.Response, response.Fields)
 }
 
 type CardSelfResponse struct {
 	Training bool `json:"training" example:"false"`
-	Quality  uint `json:"quality" example:"3"` // Min 0 - Max 4
+	Quality  uint `json:"quality" example:"3"` // 1: Again - 2: Hard - 3: Good - 4: Easy
 	CardID   uint `json:"card_id" example:"1"`
+	Cloze    uint `json:"cloze" example:"0"`
 	Card     Card
 }
 
+// IsSuccess returns if the self evaluation grade is a passed review
+func (response *CardSelfResponse) IsSuccess() bool {
+	mem := new(Mem)
+	mem.ComputeQualitySelfEvaluated(response.Quality)
+	return mem.IsSuccess()
+}
+
 // CardResponseValidation struct
 type CardResponseValidation struct {
+	Validate bool              `json:"validate" example:"true"`
+	Message  string            `json:"message" example:"Correct answer"`
+	Answer   string            `json:"correct_answer" example:"42"`
+	Matched  string            `json:"matched_answer" example:"42"` // Accepted answer matched by the response
+	Almost   bool              `json:"almost" example:"false"`      // Response is a near-miss of the matched answer
+	Partial  bool              `json:"partial" example:"false"`     // Only some fields of a multi-field response are correct
+	Score    float64           `json:"score" example:"1"`           // Share of correct fields, near-misses count as half
+	Fields   []FieldValidation `json:"fields"`
+	Diff     []DiffPart        `json:"diff"`   // Token diff between the response and the closest accepted answer
+	Errors   []AnswerError     `json:"errors"` // Kinds of errors found in the diff
+}
+
+// DiffOp enum type
+type DiffOp string
+
+const (
+	DiffEqual   DiffOp = "equal"
+	DiffMissing DiffOp = "missing" // Token of the answer missing from the response
+	DiffExtra   DiffOp = "extra"   // Token of the response not in the answer
+	DiffReplace DiffOp = "replace" // Token of the response replacing a token of the answer
+)
+
+// DiffPart struct
+type DiffPart struct {
+	Op       DiffOp `json:"op" example:"replace"`
+	Text     string `json:"text" example:"Tchaikovski"`
+	Expected string `json:"expected,omitempty" example:"Tchaikovsky"`
+}
+
+// AnswerError enum type
+type AnswerError string
+
+const (
+	AnswerErrorMissingWord   AnswerError = "missing_word"
+	AnswerErrorExtraWord     AnswerError = "extra_word"
+	AnswerErrorTransposition AnswerError = "transposition"
+	AnswerErrorAccent        AnswerError = "accent"
+	AnswerErrorTypo          AnswerError = "typo"
+	AnswerErrorWrongWord     AnswerError = "wrong_word"
+)
+
+// FieldValidation struct
+type FieldValidation struct {
+	Field    string `json:"field" example:"Date"`
 	Validate bool   `json:"validate" example:"true"`
-	Message  string `json:"message" example:"Correct answer"`
-	Answer   string `json:"correct_answer" example:"42"`
+	Almost   bool   `json:"almost" example:"false"`
+	Answer   string `json:"correct_answer" example:"1789"`
+	Matched  string `json:"matched_answer" example:"1789"`
 }
 
 func (validation *CardResponseValidation) SetCorrect() {
@@ -60,6 +216,44 @@ func (validation *CardResponseValidation) SetCorrect() {
 	validation.Message = "Correct answer"
 }
 
+func (validation *CardResponseValidation) SetAlmost() {
+	validation.Validate = true
+	validation.Almost = true
+	validation.Message = "Almost correct answer"
+}
+
+func (validation *CardResponseValidation) SetPartial(score float64) {
+	validation.Validate = false
+	validation.Partial = true
+	validation.Score = score
+	validation.Message = "Partially correct answer"
+}
+
+// SetFields sets the validation of a multi-field response from the result of each field
+func (validation *CardResponseValidation) SetFields(fields []FieldValidation, score float64) {
+	validation.Fields = fields
+
+	almost := false
+	for i := range fields {
+		if !fields[i].Validate {
+			if score > 0 {
+				validation.SetPartial(score)
+			} else {
+				validation.SetIncorrect()
+			}
+			return
+		}
+		almost = almost || fields[i].Almost
+	}
+
+	if almost {
+		validation.SetAlmost()
+	} else {
+		validation.SetCorrect()
+	}
+	validation.Score = score
+}
+
 func (validation *CardResponseValidation) SetIncorrect() {
 	validation.Validate = false
 	validation.Message = "Incorrect answer"
@@ -69,14 +263,28 @@ func (validation *CardResponseValidation) SetIncorrect() {
 type ResponseCard struct {
 	Card          Card
 	Answers       []string
-	LearningStage LearningStage `json:"learning_stage"`
+	Options       []McqOption       `json:"options"`
+	Previews      []IntervalPreview `json:"previews"` // Next review of each self evaluation grade
+	Cloze         uint              `json:"cloze"`
+	LearningStage LearningStage     `json:"learning_stage"`
+	NextDate      time.Time         `json:"next_date" example:"01/01/2000"`
 }
 
 // Set ResponseCard values
-func (responseCard *ResponseCard) Set(memdate *MemDate, answers []string) {
-	responseCard.Answers = answers
+func (responseCard *ResponseCard) Set(memdate *MemDate, options []McqOption) {
+	responseCard.Options = options
+	responseCard.Answers = make([]string, len(options))
+	for i := range options {
+		responseCard.Answers[i] = options[i].Answer
+	}
 	responseCard.Card = memdate.Card
+	responseCard.Cloze = memdate.Cloze
+	if memdate.Card.Type == CardCloze {
+		responseCard.Card.Question = memdate.Card.GetClozeQuestion(memdate.Cloze)
+		responseCard.Card.Answer = memdate.Card.GetClozeAnswer(memdate.Cloze)
+	}
 	responseCard.LearningStage = memdate.LearningStage
+	responseCard.NextDate = memdate.NextDate
 }
 
 // ResponseAuth struct
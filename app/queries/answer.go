@@ -0,0 +1,19 @@
+package queries
+
+import (
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/database"
+)
+
+// FetchAnswers returns the alternative answers of a card
+func FetchAnswers(cardID uint) []models.Answer {
+	db := database.DBConn // DB Conn
+
+	var answers []models.Answer
+
+	if err := db.Where("answers.card_id = ?", cardID).Find(&answers).Error; err != nil {
+		return make([]models.Answer, 0)
+	}
+
+	return answers
+}
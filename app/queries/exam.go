@@ -0,0 +1,193 @@
+package queries
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/core"
+	"github.com/memnix/memnixrest/pkg/database"
+	"github.com/memnix/memnixrest/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// CreateExam generates an exam on random cards of a deck
+func CreateExam(user *models.User, deck *models.Deck, config *models.ExamConfig) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	var cards []models.Card
+
+	if err := db.Where("cards.deck_id = ?", deck.ID).Order("random()").Limit(int(config.Count)).Find(&cards).Error; err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	if len(cards) == 0 {
+		res.GenerateError(utils.ErrorRequestFailed) // Empty deck
+		return res
+	}
+
+	exam := &models.Exam{
+		UserID:    user.ID,
+		DeckID:    deck.ID,
+		TimeLimit: config.TimeLimit,
+		Schedule:  config.Schedule,
+		Status:    models.ExamStarted,
+		Questions: make([]models.ExamQuestion, len(cards)),
+	}
+
+	rand.Seed(time.Now().UnixNano())
+
+	for i := range cards {
+		exam.Questions[i] = generateExamQuestion(&cards[i], deck, config.Mcq)
+	}
+
+	if err := db.Create(exam).Error; err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	res.GenerateSuccess("Success create exam", *exam, len(exam.Questions))
+	return res
+}
+
+// generateExamQuestion returns the question asked for a card
+// A cloze card asks a random cloze and a card with a mcq is asked as a mcq for mcqPercent of the questions
+func generateExamQuestion(card *models.Card, deck *models.Deck, mcqPercent uint) models.ExamQuestion {
+	question := models.ExamQuestion{
+		CardID:   card.ID,
+		Type:     card.Type,
+		Question: card.Question,
+		Format:   card.Format,
+		Image:    card.Image,
+	}
+
+	if card.Type == models.CardCloze {
+		clozes := card.GetClozes()
+		question.Cloze = clozes[rand.Intn(len(clozes))]
+		question.Question = card.GetClozeQuestion(question.Cloze)
+		return question
+	}
+
+	if card.McqID.Int32 == 0 || len(card.GetFields()) != 0 || (card.Type != models.CardMCQ && uint(rand.Intn(100)) >= mcqPercent) {
+		return question
+	}
+
+	if options := core.SelectDistractors(card.GetMCQPool(), card.Answer, deck.GetMcqOptions(), nil, false); len(options) != 0 {
+		question.SetOptions(options)
+	}
+
+	return question
+}
+
+// FetchExam returns an exam of a user with its questions
+func FetchExam(userID, examID uint) (*models.Exam, error) {
+	db := database.DBConn // DB Conn
+
+	exam := new(models.Exam)
+
+	if err := db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("exam_questions.id")
+	}).Where("exams.user_id = ? AND exams.id = ?", userID, examID).First(&exam).Error; err != nil {
+		return nil, err
+	}
+
+	return exam, nil
+}
+
+// SubmitExam scores the responses of an exam with core.ValidateResponse
+// If the exam was created with Schedule, answered questions are posted as reviews unless the submission is late
+func SubmitExam(user *models.User, exam *models.Exam, submission *models.ExamSubmission) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	if exam.Status == models.ExamSubmitted {
+		res.GenerateError(utils.ErrorExamSubmitted)
+		return res
+	}
+
+	now := time.Now()
+	late := exam.IsLate(now)
+
+	responses := make(map[uint]models.ExamAnswer, len(submission.Answers))
+	for i := range submission.Answers {
+		responses[submission.Answers[i].QuestionID] = submission.Answers[i]
+	}
+
+	for i := range exam.Questions {
+		question := &exam.Questions[i]
+		answer := responses[question.ID]
+
+		text := answer.Response
+		if text == "" && len(answer.Fields) != 0 {
+			text = strings.Join(answer.Fields, " "+utils.FieldSeparator+" ")
+		}
+
+		card := new(models.Card)
+		if err := db.Joins("Deck").First(&card, question.CardID).Error; err != nil {
+			question.SetValidation(text, new(models.CardResponseValidation)) // Deleted card
+			continue
+		}
+
+		response := &models.CardResponse{CardID: card.ID, Cloze: question.Cloze, Response: answer.Response, Fields: answer.Fields}
+		validation := core.ValidateResponse(response, card, FetchAnswers(card.ID), question.Type == models.CardMCQ)
+
+		question.SetValidation(text, validation)
+		question.Answer = card.Answer
+
+		if exam.Schedule && !late && text != "" {
+			_ = PostMem(user, card, response, validation)
+		}
+	}
+
+	exam.Grade(now)
+	for i := range exam.Questions {
+		db.Save(&exam.Questions[i])
+	}
+	db.Omit("Questions").Save(exam)
+
+	res.GenerateSuccess("Success submit exam", *exam, len(exam.Questions))
+	return res
+}
+
+// FetchUserExams returns the latest exams of a user on a deck without their questions
+func FetchUserExams(userID, deckID uint) ([]models.Exam, error) {
+	db := database.DBConn // DB Conn
+
+	var exams []models.Exam
+
+	if err := db.Where("exams.user_id = ? AND exams.deck_id = ?", userID, deckID).Order("exams.id desc").Limit(utils.MaxExamResults).Find(&exams).Error; err != nil {
+		return nil, err
+	}
+
+	return exams, nil
+}
+
+// FetchExamResults returns the latest submitted exams of every user on a deck
+func FetchExamResults(deckID uint) ([]models.ExamResult, error) {
+	db := database.DBConn // DB Conn
+
+	var exams []models.Exam
+
+	if err := db.Joins("User").Where("exams.deck_id = ? AND exams.status = ?", deckID, models.ExamSubmitted).Order("exams.submitted_at desc").Limit(utils.MaxExamResults).Find(&exams).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]models.ExamResult, len(exams))
+	for i := range exams {
+		results[i] = models.ExamResult{
+			ExamID:      exams[i].ID,
+			Score:       exams[i].Score,
+			Correct:     exams[i].Correct,
+			Total:       exams[i].Total,
+			Duration:    exams[i].Duration,
+			Late:        exams[i].Late,
+			SubmittedAt: exams[i].SubmittedAt,
+		}
+		results[i].User.Set(&exams[i].User)
+	}
+
+	return results, nil
+}
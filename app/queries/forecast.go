@@ -0,0 +1,73 @@
+package queries
+
+import (
+	"sort"
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/database"
+)
+
+// FetchForecast returns the number of cards due for each of the next days
+// Overdue cards are counted in today's forecast
+func FetchForecast(user *models.User, days int) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	var rows []struct {
+		DeckID        uint
+		NextDate      time.Time
+		LearningStage models.LearningStage
+	}
+
+	today := user.GetDayStart(time.Now())
+
+	if err := db.Table("mem_dates").Select("mem_dates.deck_id, mem_dates.next_date, mem_dates.learning_stage").Joins(
+		"left join accesses ON mem_dates.deck_id = accesses.deck_id AND accesses.user_id = ?", user.ID).Where(
+		"mem_dates.user_id = ? AND mem_dates.next_date < ? AND mem_dates.deleted_at IS NULL AND mem_dates.suspended IS false AND accesses.permission >= ? AND accesses.toggle_today IS true",
+		user.ID, today.AddDate(0, 0, days), models.AccessStudent).Scan(&rows).Error; err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	forecast := make([]models.ForecastDay, days)
+	decks := make([]map[uint]*models.ForecastDeck, days)
+
+	for i := range forecast {
+		forecast[i].Date = today.AddDate(0, 0, i)
+		forecast[i].Stages = make(map[models.LearningStage]int)
+		decks[i] = make(map[uint]*models.ForecastDeck)
+	}
+
+	for i := range rows {
+		day := 0
+		if rows[i].NextDate.After(today) {
+			day = int(user.GetDayStart(rows[i].NextDate).Sub(today).Hours()/24 + 0.5)
+		}
+
+		forecast[day].Count++
+		forecast[day].Stages[rows[i].LearningStage]++
+
+		deck, ok := decks[day][rows[i].DeckID]
+		if !ok {
+			deck = &models.ForecastDeck{DeckID: rows[i].DeckID, Stages: make(map[models.LearningStage]int)}
+			decks[day][rows[i].DeckID] = deck
+		}
+		deck.Count++
+		deck.Stages[rows[i].LearningStage]++
+	}
+
+	for i := range forecast {
+		forecast[i].Decks = make([]models.ForecastDeck, 0, len(decks[i]))
+		for _, deck := range decks[i] {
+			forecast[i].Decks = append(forecast[i].Decks, *deck)
+		}
+
+		sort.Slice(forecast[i].Decks, func(a, b int) bool {
+			return forecast[i].Decks[a].DeckID < forecast[i].Decks[b].DeckID
+		})
+	}
+
+	res.GenerateSuccess("Success getting forecast", forecast, len(forecast))
+	return res
+}
@@ -0,0 +1,51 @@
+package queries
+
+import (
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/database"
+	"github.com/memnix/memnixrest/pkg/utils"
+)
+
+// FetchLeechReport returns the cards of a deck with the highest leech rate across subscribers
+func FetchLeechReport(deckID uint) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	var reports []models.LeechReport
+
+	if err := db.Table("mem_dates").Select(
+		"mem_dates.card_id, count(*) as subscribers, sum(case when mem_dates.leech then 1 else 0 end) as leeches, sum(mem_dates.lapses) as lapses").Where(
+		"mem_dates.deck_id = ? AND mem_dates.deleted_at IS NULL", deckID).Group("mem_dates.card_id").Having(
+		"sum(mem_dates.lapses) > 0").Order("sum(case when mem_dates.leech then 1 else 0 end) * 1.0 / count(*) desc, sum(mem_dates.lapses) desc").Limit(utils.MaxLeechReport).Scan(&reports).Error; err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	cardIDs := make([]uint, len(reports))
+	for i := range reports {
+		cardIDs[i] = reports[i].CardID
+	}
+
+	var cards []models.Card
+	if len(cardIDs) != 0 {
+		if err := db.Where("cards.id IN ?", cardIDs).Find(&cards).Error; err != nil {
+			res.GenerateError(err.Error())
+			return res
+		}
+	}
+
+	cardsByID := make(map[uint]models.Card, len(cards))
+	for i := range cards {
+		cardsByID[cards[i].ID] = cards[i]
+	}
+
+	for i := range reports {
+		reports[i].Card = cardsByID[reports[i].CardID]
+		if reports[i].Subscribers != 0 {
+			reports[i].LeechRate = float64(reports[i].Leeches) / float64(reports[i].Subscribers)
+		}
+	}
+
+	res.GenerateSuccess("Success getting leech report", reports, len(reports))
+	return res
+}
@@ -0,0 +1,251 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/core"
+	"github.com/memnix/memnixrest/pkg/database"
+	"github.com/memnix/memnixrest/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// FetchMemDate returns the memDate of a user on a given card and cloze
+func FetchMemDate(userID, cardID, cloze uint) (*models.MemDate, error) {
+	return fetchMemDate(database.DBConn, userID, cardID, cloze)
+}
+
+// fetchMemDate returns the memDate of a user on a given card and cloze through db
+func fetchMemDate(db *gorm.DB, userID, cardID, cloze uint) (*models.MemDate, error) {
+	memDate := new(models.MemDate)
+
+	if err := db.Joins("Card").Joins("User").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.card_id = ? AND mem_dates.cloze = ?",
+		userID, cardID, cloze).First(&memDate).Error; err != nil {
+		return nil, err
+	}
+
+	return memDate, nil
+}
+
+// FetchCardMemDates returns the memDates of a user on every cloze of a given card
+func FetchCardMemDates(userID, cardID uint) ([]models.MemDate, error) {
+	db := database.DBConn // DB Conn
+
+	var memDates []models.MemDate
+
+	if err := db.Joins("Card").Joins("User").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.card_id = ?",
+		userID, cardID).Order("mem_dates.cloze").Find(&memDates).Error; err != nil {
+		return nil, err
+	}
+
+	if len(memDates) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return memDates, nil
+}
+
+// SuspendMemDate suspends or unsuspends every cloze of a card for a user
+func SuspendMemDate(user *models.User, cardID uint, suspended bool) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	memDates, err := FetchCardMemDates(user.ID, cardID)
+	if err != nil {
+		res.GenerateError(utils.ErrorRequestFailed) // MemDate not found
+		return res
+	}
+
+	for i := range memDates {
+		memDates[i].Suspend(suspended)
+		db.Save(&memDates[i])
+	}
+
+	res.GenerateSuccess("Success suspend memDate", memDates, len(memDates))
+	return res
+}
+
+// BuryMemDate hides every cloze of a card until the next user day
+func BuryMemDate(user *models.User, cardID uint) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	memDates, err := FetchCardMemDates(user.ID, cardID)
+	if err != nil {
+		res.GenerateError(utils.ErrorRequestFailed) // MemDate not found
+		return res
+	}
+
+	for i := range memDates {
+		memDates[i].Bury(user)
+		db.Save(&memDates[i])
+	}
+
+	res.GenerateSuccess("Success bury memDate", memDates, len(memDates))
+	return res
+}
+
+// resetMemDate resets a memDate and starts a new default mem
+func resetMemDate(user *models.User, memDate *models.MemDate) {
+	db := database.DBConn // DB Conn
+
+	memDate.Reset(user)
+	db.Save(memDate)
+
+	mem := new(models.Mem)
+	mem.FillDefaultValues(user.ID, memDate.CardID, memDate.Cloze)
+	mem.Quality = models.MemQualityNone
+	mem.Reset = true
+	db.Create(mem)
+}
+
+// ResetMemDate resets the progress on every cloze of a card for a user
+func ResetMemDate(user *models.User, cardID uint) *models.ResponseHTTP {
+	res := new(models.ResponseHTTP)
+
+	memDates, err := FetchCardMemDates(user.ID, cardID)
+	if err != nil {
+		res.GenerateError(utils.ErrorRequestFailed) // MemDate not found
+		return res
+	}
+
+	for i := range memDates {
+		resetMemDate(user, &memDates[i])
+	}
+
+	res.GenerateSuccess("Success reset memDate", memDates, len(memDates))
+	return res
+}
+
+// ResetDeckMemDates resets every card progress of a deck for a user
+func ResetDeckMemDates(user *models.User, deckID uint) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	var memDates []models.MemDate
+
+	if err := db.Where("mem_dates.user_id = ? AND mem_dates.deck_id = ?", user.ID, deckID).Find(&memDates).Error; err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	for i := range memDates {
+		resetMemDate(user, &memDates[i])
+	}
+
+	res.GenerateSuccess("Success reset deck memDates", nil, len(memDates))
+	return res
+}
+
+// CramMemDates pulls back the cards of a user on a deck due on or after the exam day, so they are seen again before it
+func CramMemDates(user *models.User, deckID uint, examDate time.Time) {
+	db := database.DBConn // DB Conn
+
+	if examDate.IsZero() {
+		return
+	}
+
+	var memDates []models.MemDate
+
+	if err := db.Where("mem_dates.user_id = ? AND mem_dates.deck_id = ? AND mem_dates.next_date >= ?",
+		user.ID, deckID, core.ExamDayStart(user, examDate)).Find(&memDates).Error; err != nil {
+		return
+	}
+
+	now := time.Now()
+	for i := range memDates {
+		db.Model(&memDates[i]).Update("next_date", core.CramNextDate(memDates[i].NextDate, user, examDate, now))
+	}
+}
+
+// UndoLastReview restores the card of the user last review to its state before that review
+// Only the user last utils.MaxUndoReviews reviews can be undone, undone reviews included
+// A card reset can't be undone and the reviews before it are kept
+func UndoLastReview(user *models.User) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	var mems []models.Mem
+
+	// Undone mems are soft deleted and still count in the window
+	if err := db.Unscoped().Where("mems.user_id = ?", user.ID).Order("mems.id desc").Limit(utils.MaxUndoReviews).Find(&mems).Error; err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	type memKey struct{ cardID, cloze uint }
+	seen := make(map[memKey]bool)
+
+	// Only the newest mem of a card can be undone, so the search stops at a reset
+	var mem *models.Mem
+	for i := range mems {
+		key := memKey{mems[i].CardID, mems[i].Cloze}
+		if mems[i].DeletedAt.Valid || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if !mems[i].Reset && !mems[i].PreviousDate.IsZero() {
+			mem = &mems[i]
+			break
+		}
+	}
+
+	if mem == nil {
+		res.GenerateError(utils.ErrorNoUndo)
+		return res
+	}
+
+	last := new(models.Mem)
+	if err := db.Where("mems.user_id = ? AND mems.card_id = ? AND mems.cloze = ? AND mems.id < ?", user.ID, mem.CardID, mem.Cloze, mem.ID).Order("mems.id desc").First(&last).Error; err != nil {
+		res.GenerateError(utils.ErrorNoUndo)
+		return res
+	}
+
+	memDate, err := undoMem(db, user, mem, last)
+	if err != nil {
+		res.GenerateError(utils.ErrorRequestFailed) // MemDate not found
+		return res
+	}
+
+	res.GenerateSuccess("Success undo review", *memDate, 1)
+	return res
+}
+
+// undoMem restores the memDate and the last mem through db to their state before the review which created mem
+func undoMem(db *gorm.DB, user *models.User, mem, last *models.Mem) (*models.MemDate, error) {
+	memDate, err := fetchMemDate(db, user.ID, mem.CardID, mem.Cloze)
+	if err != nil {
+		return nil, err
+	}
+
+	if !last.Training && !mem.PreviousStage.IsStep() {
+		access := new(models.Access)
+		if err = db.Where("accesses.user_id = ? AND accesses.deck_id = ?", user.ID, memDate.DeckID).First(&access).Error; err == nil {
+			access.RemoveTodayReview(user.GetDayStart(time.Now()), mem.PreviousStage == models.StageNeverSeen)
+			if err = db.Save(access).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	memDate.Restore(mem, last)
+
+	last.Quality = models.MemQualityNone
+	last.Training = false
+	last.Response = ""
+	last.Leeched = false
+	if err = db.Save(last).Error; err != nil {
+		return nil, err
+	}
+
+	if err = db.Delete(mem).Error; err != nil {
+		return nil, err
+	}
+
+	if err = db.Save(memDate).Error; err != nil {
+		return nil, err
+	}
+
+	return memDate, nil
+}
@@ -0,0 +1,103 @@
+package queries
+
+import (
+	"strings"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/database"
+	"github.com/memnix/memnixrest/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// OverrideReview re-grades the user last rejected response on a card as accepted and records the override
+func OverrideReview(user *models.User, card *models.Card, cloze uint) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	var mems []models.Mem
+
+	// The last mem is the one created by the review and the previous one holds its verdict
+	if err := db.Where("mems.user_id = ? AND mems.card_id = ? AND mems.cloze = ?", user.ID, card.ID, cloze).Order("mems.id desc").Limit(2).Find(&mems).Error; err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	if len(mems) != 2 || !mems[1].IsOverridable(&mems[0]) {
+		res.GenerateError(utils.ErrorNoOverride)
+		return res
+	}
+
+	mem, last := &mems[0], &mems[1]
+	response := &models.CardResponse{CardID: card.ID, Cloze: cloze, Response: last.Response, Training: last.Training}
+
+	validation := new(models.CardResponseValidation)
+	validation.SetCorrect()
+	validation.Answer = card.Answer
+	if card.Type == models.CardCloze {
+		validation.Answer = card.GetClozeAnswer(cloze)
+	}
+	validation.Matched = response.Response
+
+	// Undoing, re-grading and recording the override must not be applied partially
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		memDate, err := undoMem(tx, user, mem, last)
+		if err != nil {
+			return err
+		}
+
+		if _, err = postMem(tx, user, card, response, validation); err != nil {
+			return err
+		}
+
+		return tx.Create(&models.Override{UserID: user.ID, CardID: card.ID, DeckID: memDate.DeckID, Cloze: cloze, Response: response.Response}).Error
+	}); err != nil {
+		res.GenerateError(utils.ErrorRequestFailed)
+		return res
+	}
+
+	res.GenerateSuccess("Success override review", *validation, 1)
+	return res
+}
+
+// FetchOverrideReport returns the most frequently overridden responses of a deck
+func FetchOverrideReport(deckID uint) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	var reports []models.OverrideReport
+
+	if err := db.Table("overrides").Select(
+		"overrides.card_id, overrides.cloze, min(overrides.response) as response, count(*) as count").Where(
+		"overrides.deck_id = ? AND overrides.deleted_at IS NULL", deckID).Group(
+		"overrides.card_id, overrides.cloze, lower(trim(overrides.response))").Order(
+		"count desc").Limit(utils.MaxOverrideReport).Scan(&reports).Error; err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	cardIDs := make([]uint, len(reports))
+	for i := range reports {
+		cardIDs[i] = reports[i].CardID
+	}
+
+	var cards []models.Card
+	if len(cardIDs) != 0 {
+		if err := db.Where("cards.id IN ?", cardIDs).Find(&cards).Error; err != nil {
+			res.GenerateError(err.Error())
+			return res
+		}
+	}
+
+	cardsByID := make(map[uint]models.Card, len(cards))
+	for i := range cards {
+		cardsByID[cards[i].ID] = cards[i]
+	}
+
+	for i := range reports {
+		reports[i].Card = cardsByID[reports[i].CardID]
+		reports[i].Response = strings.TrimSpace(reports[i].Response)
+	}
+
+	res.GenerateSuccess("Success getting override report", reports, len(reports))
+	return res
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,7 +36,7 @@ func UpdateSubUsers(card *models.Card, user *models.User) error {
 	}
 
 	for i := range users {
-		_ = GenerateMemDate(users[i].ID, card.ID, card.DeckID)
+		_ = GenerateMemDate(&users[i], card)
 	}
 
 	return nil
@@ -188,57 +189,142 @@ func CheckDeckLimit(user *models.User) bool {
 	return true
 }
 
-// PostSelfEvaluatedMem updates Mem & MemDate
-func PostSelfEvaluatedMem(user *models.User, card *models.Card, quality uint, training bool) *models.ResponseHTTP {
+// PostSelfEvaluatedMem updates Mem & MemDate from a self evaluation grade and returns the memDate
+func PostSelfEvaluatedMem(user *models.User, card *models.Card, cloze, grade uint, training bool) *models.ResponseHTTP {
 	db := database.DBConn // DB Conn
 	res := new(models.ResponseHTTP)
 
 	memDate := new(models.MemDate)
 
-	if err := db.Joins("Card").Joins("User").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.card_id = ?",
-		user.ID, card.ID).First(&memDate).Error; err != nil {
+	if err := db.Joins("Card").Joins("User").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.card_id = ? AND mem_dates.cloze = ?",
+		user.ID, card.ID, cloze).First(&memDate).Error; err != nil {
 		res.GenerateError(utils.ErrorRequestFailed) // MemDate not found
 		// TODO: Create a default MemDate
 		return res
 	}
 
-	exMem := FetchMem(memDate.CardID, user.ID)
+	exMem := FetchMem(memDate.CardID, user.ID, memDate.Cloze)
 	if exMem.Efactor == 0 {
-		exMem.FillDefaultValues(user.ID, card.ID)
+		exMem.FillDefaultValues(user.ID, card.ID, memDate.Cloze)
 	}
 
-	core.UpdateMemSelfEvaluated(exMem, training, quality)
+	isNew, isStep := memDate.LearningStage == models.StageNeverSeen, memDate.LearningStage.IsStep()
+	core.UpdateMemSelfEvaluated(exMem, memDate, training, grade)
+	if !training && !isStep {
+		UpdateDailyCounters(db, user, memDate.DeckID, isNew)
+	}
 
-	res.GenerateSuccess("Success Post Mem", nil, 0)
+	res.GenerateSuccess("Success Post Mem", *memDate, 1)
 	return res
 }
 
-// PostMem updates MemDate & Mem
-func PostMem(user *models.User, card *models.Card, validation *models.CardResponseValidation, training bool) *models.ResponseHTTP {
+// PostMem updates MemDate & Mem and returns the memDate
+func PostMem(user *models.User, card *models.Card, response *models.CardResponse, validation *models.CardResponseValidation) *models.ResponseHTTP {
 	db := database.DBConn // DB Conn
 	res := new(models.ResponseHTTP)
 
+	memDate, err := postMem(db, user, card, response, validation)
+	if err != nil {
+		res.GenerateError(utils.ErrorRequestFailed)
+		return res
+	}
+
+	res.GenerateSuccess("Success Post Mem", *memDate, 1)
+	return res
+}
+
+// postMem grades the last mem of a card with validation through db, which may be a transaction
+func postMem(db *gorm.DB, user *models.User, card *models.Card, response *models.CardResponse, validation *models.CardResponseValidation) (*models.MemDate, error) {
 	memDate := new(models.MemDate)
 
-	if err := db.Joins("Card").Joins("User").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.card_id = ?",
-		user.ID, card.ID).First(&memDate).Error; err != nil {
-		res.GenerateError(utils.ErrorRequestFailed) // MemDate not found
+	if err := db.Joins("Card").Joins("User").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.card_id = ? AND mem_dates.cloze = ?",
+		user.ID, card.ID, response.Cloze).First(&memDate).Error; err != nil {
 		// TODO: Create a default MemDate
-		return res
+		return nil, err
 	}
 
-	exMem := FetchMem(memDate.CardID, user.ID)
+	exMem := fetchMem(db, memDate.CardID, user.ID, memDate.Cloze)
 	if exMem.Efactor == 0 {
-		exMem.FillDefaultValues(user.ID, card.ID)
+		exMem.FillDefaultValues(user.ID, card.ID, memDate.Cloze)
 	}
+	exMem.Response = response.Response
 
-	if training {
-		core.UpdateMemTraining(exMem, validation.Validate)
-	} else {
-		core.UpdateMem(exMem, validation.Validate)
+	if response.Training {
+		return memDate, core.UpdateMemTraining(db, exMem, memDate, validation)
 	}
-	res.GenerateSuccess("Success Post Mem", nil, 0)
-	return res
+
+	isNew, isStep := memDate.LearningStage == models.StageNeverSeen, memDate.LearningStage.IsStep()
+	if err := core.UpdateMem(db, exMem, memDate, validation); err != nil {
+		return nil, err
+	}
+	if !isStep {
+		UpdateDailyCounters(db, user, memDate.DeckID, isNew)
+	}
+
+	return memDate, nil
+}
+
+// UpdateDailyCounters counts a review in the user access to a deck through db
+func UpdateDailyCounters(db *gorm.DB, user *models.User, deckID uint, isNew bool) {
+	access := new(models.Access)
+
+	if err := db.Where("accesses.user_id = ? AND accesses.deck_id = ?", user.ID, deckID).First(&access).Error; err != nil {
+		log := models.CreateLog(fmt.Sprintf("Error from %s on deck %d - UpdateDailyCounters: %s", user.Email, deckID, err.Error()),
+			models.LogQueryGetError).SetType(models.LogTypeError).AttachIDs(user.ID, deckID, 0)
+		_ = log.SendLog()
+		return
+	}
+
+	access.AddTodayReview(user.GetDayStart(time.Now()), isNew)
+
+	db.Save(access)
+}
+
+// ApplyDailyLimits removes the memDates exceeding the daily limits of each access
+// Cards in a learning or relearning step are never held back
+// It returns the kept memDates and the number of held back memDates by deck
+func ApplyDailyLimits(user *models.User, memDates []models.MemDate) ([]models.MemDate, map[uint]int) {
+	db := database.DBConn // DB Conn
+
+	var accesses []models.Access
+	heldBack := make(map[uint]int)
+
+	if err := db.Where("accesses.user_id = ? AND accesses.permission >= ?", user.ID, models.AccessStudent).Find(&accesses).Error; err != nil {
+		return memDates, heldBack
+	}
+
+	dayStart := user.GetDayStart(time.Now())
+	remainingNew := make(map[uint]uint, len(accesses))
+	remainingReviews := make(map[uint]uint, len(accesses))
+
+	for i := range accesses {
+		remainingNew[accesses[i].DeckID] = accesses[i].GetRemainingNew(dayStart)
+		remainingReviews[accesses[i].DeckID] = accesses[i].GetRemainingReviews(dayStart)
+	}
+
+	result := make([]models.MemDate, 0, len(memDates))
+
+	for i := range memDates {
+		if memDates[i].LearningStage.IsStep() {
+			result = append(result, memDates[i])
+			continue
+		}
+
+		remaining := remainingReviews
+		if memDates[i].LearningStage == models.StageNeverSeen {
+			remaining = remainingNew
+		}
+
+		if remaining[memDates[i].DeckID] == 0 {
+			heldBack[memDates[i].DeckID]++
+			continue
+		}
+
+		remaining[memDates[i].DeckID]--
+		result = append(result, memDates[i])
+	}
+
+	return result, heldBack
 }
 
 // PopulateMemDate with default value for a given user & deck
@@ -254,7 +340,7 @@ func PopulateMemDate(user *models.User, deck *models.Deck) *models.ResponseHTTP
 	}
 
 	for i := range cards {
-		_ = GenerateMemDate(user.ID, cards[i].ID, cards[i].DeckID)
+		_ = GenerateMemDate(user, &cards[i])
 	}
 	res.GenerateSuccess("Success generated mem_date", nil, 0)
 	return res
@@ -276,31 +362,49 @@ func GetSubUsers(deckID uint) *models.ResponseHTTP {
 }
 
 // GenerateMemDate with default nextDate
-func GenerateMemDate(userID, cardID, deckID uint) *models.ResponseHTTP {
+// A CardCloze gets one MemDate per cloze
+func GenerateMemDate(user *models.User, card *models.Card) *models.ResponseHTTP {
 	db := database.DBConn // DB Conn
 	res := new(models.ResponseHTTP)
 
-	memDate := new(models.MemDate)
+	clozes := card.GetClozes()
+	memDates := make([]models.MemDate, len(clozes))
 
-	if err := db.Joins("User").Joins("Card").Where("mem_dates.user_id = ? AND mem_dates.card_id = ?", userID, cardID).First(&memDate).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			memDate.SetDefaultNextDate(userID, cardID, deckID)
-			db.Create(memDate)
-		} else {
-			res.GenerateError(err.Error())
-			return res
+	for i, cloze := range clozes {
+		memDate := &memDates[i]
+
+		if err := db.Joins("User").Joins("Card").Where("mem_dates.user_id = ? AND mem_dates.card_id = ? AND mem_dates.cloze = ?",
+			user.ID, card.ID, cloze).First(&memDate).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				memDate.SetDefaultNextDate(user, card.ID, card.DeckID, cloze)
+				db.Create(memDate)
+			} else {
+				res.GenerateError(err.Error())
+				return res
+			}
 		}
 	}
-	res.GenerateSuccess("Success generate MemDate", memDate, 1)
+
+	res.GenerateSuccess("Success generate MemDate", memDates, len(memDates))
 	return res
 }
 
-// FetchMem returns last mem of an user on a given card
-func FetchMem(cardID, userID uint) *models.Mem {
+// DeleteStaleClozes deletes the MemDates of clozes removed from a card
+func DeleteStaleClozes(card *models.Card) {
 	db := database.DBConn // DB Conn
 
+	db.Unscoped().Where("mem_dates.card_id = ? AND mem_dates.cloze NOT IN ?", card.ID, card.GetClozes()).Delete(&models.MemDate{})
+}
+
+// FetchMem returns last mem of an user on a given card and cloze
+func FetchMem(cardID, userID, cloze uint) *models.Mem {
+	return fetchMem(database.DBConn, cardID, userID, cloze)
+}
+
+// fetchMem returns last mem of an user on a given card and cloze through db
+func fetchMem(db *gorm.DB, cardID, userID, cloze uint) *models.Mem {
 	mem := new(models.Mem)
-	if err := db.Joins("Card").Where("mems.card_id = ? AND mems.user_id = ?", cardID, userID).Order("id desc").First(&mem).Error; err != nil {
+	if err := db.Joins("Card").Where("mems.card_id = ? AND mems.user_id = ? AND mems.cloze = ?", cardID, userID, cloze).Order("id desc").First(&mem).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			mem.Efactor = 0
 		}
@@ -308,42 +412,108 @@ func FetchMem(cardID, userID uint) *models.Mem {
 	return mem
 }
 
-// GenerateMCQ returns a list of answer
-func GenerateMCQ(memDate *models.MemDate, userID uint) []string {
-	mem := FetchMem(memDate.CardID, userID)
+// FetchConfusions returns how many times a user failed a card by giving each response
+func FetchConfusions(userID, cardID, cloze uint) map[string]int {
+	db := database.DBConn // DB Conn
+
+	var rows []struct {
+		Response string
+		Count    int
+	}
+
+	confusions := make(map[string]int)
+
+	if err := db.Model(&models.Mem{}).Select("mems.response, count(*) as count").Where("mems.user_id = ? AND mems.card_id = ? AND mems.cloze = ? AND mems.response <> '' AND mems.quality >= ? AND mems.quality < ?",
+		userID, cardID, cloze, models.MemQualityBlackout, models.MemQualityError).Group("mems.response").Scan(&rows).Error; err != nil {
+		return confusions
+	}
+
+	for i := range rows {
+		confusions[strings.TrimSpace(rows[i].Response)] += rows[i].Count
+	}
+
+	return confusions
+}
 
-	answersList := make([]string, 4)
-	if mem.IsMCQ() || memDate.Card.Type == models.CardMCQ {
-		answersList = memDate.Card.GetMCQAnswers()
-		if len(answersList) == 4 {
+// GenerateMCQ returns the list of mcq options sized by the deck
+// A CardCloze is never turned into an mcq, its answer depends on the cloze
+func GenerateMCQ(memDate *models.MemDate, mem *models.Mem, userID uint) []models.McqOption {
+	if memDate.Card.Type != models.CardCloze && (mem.IsMCQ(&memDate.Deck) || memDate.Card.Type == models.CardMCQ) {
+		options := core.SelectDistractors(memDate.Card.GetMCQPool(), memDate.Card.Answer, memDate.Deck.GetMcqOptions(),
+			FetchConfusions(userID, memDate.CardID, memDate.Cloze), mem.IsExperienced())
+		if len(options) != 0 {
 			memDate.Card.Type = models.CardMCQ // MCQ
 		}
 
-		return answersList
+		return options
 	}
 
-	return answersList
+	return make([]models.McqOption, 0)
 }
 
-// FetchTrainingCards returns training cards
-func FetchTrainingCards(userID, deckID uint) *models.ResponseHTTP {
-	res := new(models.ResponseHTTP)
+// IsAskedAsMCQ returns if a card is shown to a user as a mcq, like GenerateMCQ decides it
+func IsAskedAsMCQ(userID uint, card *models.Card, cloze uint) bool {
+	if card.Type == models.CardMCQ {
+		return true
+	}
+
+	mem := FetchMem(card.ID, userID, cloze)
+	if mem.Efactor == 0 {
+		mem.FillDefaultValues(userID, card.ID, cloze)
+	}
+
+	memDate := &models.MemDate{UserID: userID, CardID: card.ID, Card: *card, Cloze: cloze, DeckID: card.DeckID, Deck: card.Deck}
+
+	return len(GenerateMCQ(memDate, mem, userID)) != 0
+}
+
+// GenerateResponseCard returns a card to review with its mcq options and self evaluation previews
+func GenerateResponseCard(memDate *models.MemDate, userID uint) models.ResponseCard {
+	responseCard := new(models.ResponseCard)
+
+	mem := FetchMem(memDate.CardID, userID, memDate.Cloze)
+	if mem.Efactor == 0 {
+		mem.FillDefaultValues(userID, memDate.CardID, memDate.Cloze)
+	}
+
+	responseCard.Set(memDate, GenerateMCQ(memDate, mem, userID))
+	responseCard.Previews = core.PreviewSelfEvaluation(mem, memDate, core.FetchExamDate(userID, memDate.DeckID))
+
+	return *responseCard
+}
+
+// FetchTrainingMemDates returns the memDates of a user on a deck which can be trained
+func FetchTrainingMemDates(userID, deckID uint) ([]models.MemDate, error) {
 	db := database.DBConn // DB Conn
 
 	var memDates []models.MemDate
 
-	if err := db.Joins("Deck").Joins("Card").Where("mem_dates.deck_id = ? AND mem_dates.user_id = ?", deckID, userID).Find(&memDates).Error; err != nil {
+	if err := db.Joins("Deck").Joins("Card").Where("mem_dates.deck_id = ? AND mem_dates.user_id = ? AND mem_dates.suspended IS false AND (mem_dates.buried_until IS NULL OR mem_dates.buried_until <= ?)",
+		deckID, userID, time.Now()).Find(&memDates).Error; err != nil {
+		return nil, err
+	}
+
+	return memDates, nil
+}
+
+// FetchTrainingCards returns training cards
+func FetchTrainingCards(userID, deckID uint) *models.ResponseHTTP {
+	res := new(models.ResponseHTTP)
+
+	memDates, err := FetchTrainingMemDates(userID, deckID)
+	if err != nil {
 		res.GenerateError(err.Error())
 		return res
 	}
 	responseCard := new(models.ResponseCard)
-	var answersList []string
+	var options []models.McqOption
 
 	result := make([]models.ResponseCard, len(memDates))
 
 	for i := range memDates {
-		answersList = GenerateMCQ(&memDates[i], userID)
-		responseCard.Set(&memDates[i], answersList)
+		mem := FetchMem(memDates[i].CardID, userID, memDates[i].Cloze)
+		options = GenerateMCQ(&memDates[i], mem, userID)
+		responseCard.Set(&memDates[i], options)
 		result[i] = *responseCard
 	}
 
@@ -354,26 +524,48 @@ func FetchTrainingCards(userID, deckID uint) *models.ResponseHTTP {
 	return res
 }
 
-// FetchTodayCard return today cards
-func FetchTodayCard(userID uint) *models.ResponseHTTP {
+// FetchTodayMemDates returns the memDates due today for a user within the daily limits
+// Cards in a learning step are only returned once their step delay is over
+// It also returns the number of memDates held back by the limits for each deck
+func FetchTodayMemDates(user *models.User) ([]models.MemDate, map[uint]int, error) {
 	db := database.DBConn // DB Conn
-	t := time.Now()
 
-	res := new(models.ResponseHTTP)
 	var memDates []models.MemDate
 
+	now := time.Now()
+
 	if err := db.Joins(
 		"left join accesses ON mem_dates.deck_id = accesses.deck_id AND accesses.user_id = ?",
-		userID).Joins("Card").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.next_date < ? AND accesses.permission >= ? AND accesses.toggle_today IS true",
-		userID, t.AddDate(0, 0, 1).Add(
-			time.Duration(-t.Hour())*time.Hour), models.AccessStudent).Order("next_date asc").Find(&memDates).Error; err != nil {
+		user.ID).Joins("Card").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.next_date < ? AND (mem_dates.learning_stage NOT IN ? OR mem_dates.next_date <= ?) AND accesses.permission >= ? AND accesses.toggle_today IS true AND mem_dates.suspended IS false AND (mem_dates.buried_until IS NULL OR mem_dates.buried_until <= ?)",
+		user.ID, user.GetTodayEnd(), []models.LearningStage{models.StageToLearn, models.StageToRelearn}, now, models.AccessStudent, now).Order("next_date asc").Find(&memDates).Error; err != nil {
+		return nil, nil, err
+	}
+
+	memDates, heldBack := ApplyDailyLimits(user, memDates)
+
+	return memDates, heldBack, nil
+}
+
+// FetchTodayCard return today cards
+func FetchTodayCard(userID uint) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+
+	res := new(models.ResponseHTTP)
+
+	user := new(models.User)
+	if err := db.First(&user, userID).Error; err != nil {
+		res.GenerateError("Today's memDate not found")
+		return res
+	}
+
+	memDates, heldBack, err := FetchTodayMemDates(user)
+	if err != nil {
 		res.GenerateError("Today's memDate not found")
 		return res
 	}
 
 	m := make(map[uint][]models.ResponseCard)
 	wg := new(sync.WaitGroup)
-	responseCard := new(models.ResponseCard)
 
 	workers := 10
 
@@ -396,9 +588,8 @@ func FetchTodayCard(userID uint) *models.ResponseHTTP {
 		subMemDates := memDates[hi:lo]
 		go func() {
 			for index := range subMemDates {
-				answersList := GenerateMCQ(&subMemDates[index], userID)
-				responseCard.Set(&subMemDates[index], answersList)
-				ch <- *responseCard
+				subMemDates[index].User = *user
+				ch <- GenerateResponseCard(&subMemDates[index], userID)
 			}
 
 			wg.Done()
@@ -411,18 +602,26 @@ func FetchTodayCard(userID uint) *models.ResponseHTTP {
 		m[toto.Card.DeckID] = append(m[toto.Card.DeckID], toto)
 	}
 
+	for key := range heldBack {
+		if _, ok := m[key]; !ok {
+			m[key] = make([]models.ResponseCard, 0)
+		}
+	}
+
 	todayResponse := new(models.TodayResponse)
 
 	for key := range m {
 		deck := new(models.Deck)
 		_ = db.First(&deck, key).Error
 		deckResponse := models.DeckResponse{
-			DeckID: key,
-			Cards:  m[key],
-			Count:  len(m[key]),
-			Deck:   *deck,
+			DeckID:   key,
+			Cards:    m[key],
+			Count:    len(m[key]),
+			Deck:     *deck,
+			HeldBack: heldBack[key],
 		}
 		todayResponse.DecksReponses = append(todayResponse.DecksReponses, deckResponse)
+		todayResponse.HeldBack += heldBack[key]
 	}
 
 	sort.Slice(todayResponse.DecksReponses, func(i, j int) bool {
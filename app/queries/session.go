@@ -0,0 +1,157 @@
+package queries
+
+import (
+	"errors"
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/database"
+	"github.com/memnix/memnixrest/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// CreateSession finishes the user active session and starts a new one
+// A training session queues the deck cards, otherwise today cards of the deck (or of every deck if deckID is 0) are queued
+func CreateSession(user *models.User, deckID uint, training bool) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	var memDates []models.MemDate
+	var err error
+
+	if training {
+		memDates, err = FetchTrainingMemDates(user.ID, deckID)
+	} else {
+		memDates, _, err = FetchTodayMemDates(user)
+	}
+	if err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	if !training && deckID != 0 {
+		deckMemDates := make([]models.MemDate, 0, len(memDates))
+		for i := range memDates {
+			if memDates[i].DeckID == deckID {
+				deckMemDates = append(deckMemDates, memDates[i])
+			}
+		}
+		memDates = deckMemDates
+	}
+
+	now := time.Now()
+
+	if active, err := FetchActiveSession(user.ID); err == nil {
+		active.Finish(now)
+		db.Save(active)
+	}
+
+	session := new(models.Session)
+	session.Start(user.ID, deckID, training, memDates, now)
+
+	if err = db.Create(session).Error; err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	res.GenerateSuccess("Success create session", GenerateSessionResponse(user, session), 1)
+	return res
+}
+
+// FetchActiveSession returns the active session of a user
+func FetchActiveSession(userID uint) (*models.Session, error) {
+	db := database.DBConn // DB Conn
+
+	session := new(models.Session)
+
+	if err := db.Where("sessions.user_id = ? AND sessions.status = ?", userID, models.SessionActive).Order("sessions.id desc").First(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// FetchSession returns a session of a user
+func FetchSession(userID, sessionID uint) (*models.Session, error) {
+	db := database.DBConn // DB Conn
+
+	session := new(models.Session)
+
+	if err := db.Where("sessions.user_id = ? AND sessions.id = ?", userID, sessionID).First(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GenerateSessionResponse returns the session with its current card
+// MemDates which can't be reviewed anymore (suspended, buried, deleted) are dropped from the queue
+func GenerateSessionResponse(user *models.User, session *models.Session) models.SessionResponse {
+	db := database.DBConn // DB Conn
+
+	response := models.SessionResponse{}
+	queue := session.GetQueue()
+
+	for session.Status == models.SessionActive {
+		memDateID := session.Current()
+
+		memDate := new(models.MemDate)
+		err := db.Joins("Card").Joins("Deck").Where("mem_dates.id = ? AND mem_dates.user_id = ? AND mem_dates.suspended IS false AND (mem_dates.buried_until IS NULL OR mem_dates.buried_until <= ?)",
+			memDateID, user.ID, time.Now()).First(&memDate).Error
+		if err == nil {
+			memDate.User = *user
+			card := GenerateResponseCard(memDate, user.ID)
+			response.Card = &card
+			break
+		}
+
+		session.Skip(memDateID)
+	}
+
+	if len(session.GetQueue()) != len(queue) {
+		db.Save(session)
+	}
+
+	response.Session = *session
+	response.Remaining = len(session.GetQueue())
+
+	return response
+}
+
+// AdvanceSession records a review in the user active session
+// Reviews of cards outside the session queue are ignored
+// Only the review controllers call it, so overrides and exam answers never advance a session
+func AdvanceSession(user *models.User, memDate *models.MemDate, training, passed bool) {
+	db := database.DBConn // DB Conn
+
+	session, err := FetchActiveSession(user.ID)
+	if err != nil || session.Training != training {
+		return
+	}
+
+	if session.Answer(memDate.ID, passed, time.Now()) {
+		db.Save(session)
+	}
+}
+
+// EndSession finishes a session of a user and returns its summary
+func EndSession(user *models.User, sessionID uint) *models.ResponseHTTP {
+	db := database.DBConn // DB Conn
+	res := new(models.ResponseHTTP)
+
+	session, err := FetchSession(user.ID, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			res.GenerateError(utils.ErrorNoSession)
+		} else {
+			res.GenerateError(err.Error())
+		}
+		return res
+	}
+
+	session.Finish(time.Now())
+	db.Save(session)
+
+	res.GenerateSuccess("Success end session", session.GetSummary(), 1)
+	return res
+}
@@ -0,0 +1,145 @@
+package queries
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/database"
+	"gorm.io/gorm"
+)
+
+// FetchStatistics returns the review statistics of a user computed from the mems history
+// If deckID is 0, the statistics cover every deck of the user
+func FetchStatistics(user *models.User, deckID uint, days int) *models.ResponseHTTP {
+	res := new(models.ResponseHTTP)
+	statistics := new(models.Statistics)
+
+	today := user.GetDayStart(time.Now())
+	from := today.AddDate(0, 0, 1-days)
+
+	if err := fillReviewStatistics(statistics, user, deckID, from, days); err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	if err := fillStageStatistics(statistics, user.ID, deckID); err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	if err := fillEfactorStatistics(statistics, user.ID, deckID); err != nil {
+		res.GenerateError(err.Error())
+		return res
+	}
+
+	res.GenerateSuccess("Success getting statistics", *statistics, statistics.Reviews)
+	return res
+}
+
+// memsByDeck filters a mems query on a deck
+func memsByDeck(query *gorm.DB, deckID uint) *gorm.DB {
+	if deckID == 0 {
+		return query
+	}
+	return query.Joins("left join cards ON cards.id = mems.card_id").Where("cards.deck_id = ?", deckID)
+}
+
+// fillReviewStatistics computes the reviews count, the true retention and the reviews per day
+func fillReviewStatistics(statistics *models.Statistics, user *models.User, deckID uint, from time.Time, days int) error {
+	db := database.DBConn // DB Conn
+
+	var mems []models.Mem
+
+	query := db.Table("mems").Select("mems.quality, mems.learning_stage, mems.updated_at").Where(
+		"mems.user_id = ? AND mems.quality <> ? AND mems.training IS false AND mems.updated_at >= ? AND mems.deleted_at IS NULL",
+		user.ID, models.MemQualityNone, from)
+
+	if err := memsByDeck(query, deckID).Scan(&mems).Error; err != nil {
+		return err
+	}
+
+	statistics.ReviewsPerDay = make([]models.DayStatistics, days)
+	for i := range statistics.ReviewsPerDay {
+		statistics.ReviewsPerDay[i].Date = from.AddDate(0, 0, i)
+	}
+
+	matureReviews, maturePassed := 0, 0
+
+	for i := range mems {
+		day := int(user.GetDayStart(mems[i].UpdatedAt).Sub(from).Hours()/24 + 0.5)
+		if day < 0 || day >= days {
+			continue
+		}
+
+		statistics.Reviews++
+		statistics.ReviewsPerDay[day].Reviews++
+
+		if mems[i].LearningStage > models.StageToRelearn {
+			matureReviews++
+		}
+
+		if mems[i].IsSuccess() {
+			statistics.Passed++
+			statistics.ReviewsPerDay[day].Passed++
+
+			if mems[i].LearningStage > models.StageToRelearn {
+				maturePassed++
+			}
+		}
+	}
+
+	if matureReviews != 0 {
+		statistics.TrueRetention = float64(maturePassed) / float64(matureReviews)
+	}
+
+	return nil
+}
+
+// fillStageStatistics counts the cards by current learning stage
+func fillStageStatistics(statistics *models.Statistics, userID, deckID uint) error {
+	db := database.DBConn // DB Conn
+
+	var stages []struct {
+		LearningStage models.LearningStage
+		Count         int
+	}
+
+	query := db.Table("mem_dates").Select("mem_dates.learning_stage, count(*) as count").Where(
+		"mem_dates.user_id = ? AND mem_dates.deleted_at IS NULL", userID)
+	if deckID != 0 {
+		query = query.Where("mem_dates.deck_id = ?", deckID)
+	}
+
+	if err := query.Group("mem_dates.learning_stage").Scan(&stages).Error; err != nil {
+		return err
+	}
+
+	statistics.Stages = make(map[models.LearningStage]int, len(stages))
+	for i := range stages {
+		statistics.Stages[stages[i].LearningStage] = stages[i].Count
+	}
+
+	return nil
+}
+
+// fillEfactorStatistics counts the cards by efactor of their last mem, each cloze counting as a card
+func fillEfactorStatistics(statistics *models.Statistics, userID, deckID uint) error {
+	db := database.DBConn // DB Conn
+
+	var efactors []float32
+
+	query := db.Table("mems").Select("DISTINCT ON (mems.card_id, mems.cloze) mems.efactor").Where(
+		"mems.user_id = ? AND mems.deleted_at IS NULL", userID)
+
+	if err := memsByDeck(query, deckID).Order("mems.card_id, mems.cloze, mems.id desc").Scan(&efactors).Error; err != nil {
+		return err
+	}
+
+	statistics.Efactors = make(map[string]int)
+	for i := range efactors {
+		statistics.Efactors[fmt.Sprintf("%.1f", efactors[i])]++
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/memnix/memnixrest/pkg/routes"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"log"
+	_ "time/tzdata" // Embed timezones for user settings
 )
 
 // @title Memnix
@@ -59,7 +60,7 @@ func main() { // Try to connect to the database
 	// Models to migrate
 	var migrates []interface{}
 	migrates = append(migrates, models.Access{}, models.Card{}, models.Deck{},
-		models.User{}, models.Mem{}, models.Answer{}, models.MemDate{}, models.Mcq{})
+		models.User{}, models.Mem{}, models.Answer{}, models.MemDate{}, models.Mcq{}, models.McqOption{}, models.Override{}, models.Session{}, models.Exam{}, models.ExamQuestion{})
 
 	// AutoMigrate models
 	for i := 0; i < len(migrates); i++ {
@@ -69,6 +70,21 @@ func main() { // Try to connect to the database
 		}
 	}
 
+	// Move legacy mcq answers to the options table
+	if err := models.MigrateMcqAnswers(); err != nil {
+		log.Panic("Can't migrate mcq answers:", err.Error())
+	}
+
+	// Backfill the learning stage of cards reviewed before it was stored on mem dates
+	if err := models.MigrateLearningStages(); err != nil {
+		log.Panic("Can't migrate learning stages:", err.Error())
+	}
+
+	// Count the lapses of cards reviewed before they were stored on mem dates
+	if err := models.MigrateLapses(); err != nil {
+		log.Panic("Can't migrate lapses:", err.Error())
+	}
+
 	// Create the app
 	app := routes.New()
 	// Listen to port 1812
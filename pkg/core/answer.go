@@ -0,0 +1,185 @@
+package core
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/memnix/memnixrest/app/models"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// AnswerMatch enum type
+type AnswerMatch int64
+
+const (
+	MatchNone AnswerMatch = iota
+	MatchAlmost
+	MatchExact
+)
+
+// articles are the leading articles ignored by Card.IgnoreArticles for each Deck.Lang
+// Elided articles end with an apostrophe and are not followed by a space
+var articles = map[string][]string{
+	"en": {"the", "an", "a"},
+	"fr": {"les", "le", "la", "l'", "l’", "des", "une", "un"},
+	"es": {"los", "las", "el", "la", "unos", "unas", "una", "un"},
+	"it": {"gli", "il", "lo", "la", "le", "i", "l'", "l’", "uno", "una", "un", "un'", "un’"},
+	"de": {"der", "die", "das", "den", "dem", "des", "eine", "einen", "einem", "einer", "eines", "ein"},
+	"pt": {"os", "as", "o", "a", "umas", "uns", "uma", "um"},
+}
+
+// ValidateAnswer checks a response against Card.Answer and the card alternative answers
+// It returns the accepted answer matched by the response and how close the match is
+func ValidateAnswer(response string, card *models.Card, answers []models.Answer) (string, AnswerMatch) {
+	accepted := make([]string, 0, len(answers)+1)
+	accepted = append(accepted, card.Answer)
+	for i := range answers {
+		accepted = append(accepted, answers[i].Answer)
+	}
+
+	switch card.Type {
+	case models.CardInt:
+		return validateNumber(response, card, accepted)
+	case models.CardExpression:
+		return validateExpression(response, accepted)
+	}
+
+	normalized := make([]string, len(accepted))
+	respString := normalizeAnswer(response, card)
+
+	for i := range accepted {
+		normalized[i] = normalizeAnswer(accepted[i], card)
+		if respString == normalized[i] {
+			return accepted[i], MatchExact
+		}
+	}
+
+	if !card.AllowTypos {
+		return "", MatchNone
+	}
+
+	best, bestDistance := -1, 0
+	respRunes := []rune(respString)
+	for i := range normalized {
+		answerRunes := []rune(normalized[i])
+		distance := levenshtein(respRunes, answerRunes)
+		if distance <= maxTypos(len(answerRunes)) && (best == -1 || distance < bestDistance) {
+			best, bestDistance = i, distance
+		}
+	}
+
+	if best == -1 {
+		return "", MatchNone
+	}
+
+	return accepted[best], MatchAlmost
+}
+
+// normalizeAnswer applies the card matching policy to an answer
+func normalizeAnswer(answer string, card *models.Card) string {
+	answer = strings.TrimSpace(answer)
+
+	if card.IgnoreArticles {
+		answer = stripArticle(answer, card.Deck.Lang)
+	}
+
+	if card.IgnorePunctuation {
+		answer = strings.Map(func(r rune) rune {
+			if unicode.IsPunct(r) {
+				return -1
+			}
+			return r
+		}, answer)
+	}
+
+	if card.IgnoreAccents {
+		answer = foldAccents(answer)
+	}
+
+	if !card.Case {
+		answer = strings.ToLower(answer)
+	}
+
+	if card.Spaces {
+		return strings.Join(strings.Fields(answer), " ")
+	}
+	return strings.ReplaceAll(answer, " ", "")
+}
+
+// stripArticle removes the leading article of an answer
+// The answer is left untouched if nothing remains after the article
+func stripArticle(answer, lang string) string {
+	for _, article := range articles[lang] {
+		prefix := article
+		if !strings.HasSuffix(article, "'") && !strings.HasSuffix(article, "’") {
+			prefix += " "
+		}
+
+		if len(answer) > len(prefix) && strings.EqualFold(answer[:len(prefix)], prefix) {
+			if stripped := strings.TrimSpace(answer[len(prefix):]); stripped != "" {
+				return stripped
+			}
+		}
+	}
+
+	return answer
+}
+
+// foldAccents removes the diacritics of an answer
+func foldAccents(answer string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), answer)
+	if err != nil {
+		return answer
+	}
+	return folded
+}
+
+// maxTypos returns the edit distance tolerated for an answer length
+func maxTypos(length int) int {
+	switch {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	case length < 16:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/database"
+)
+
+// FetchExamDate returns the exam date set on the user access to a deck, zero if there is none
+func FetchExamDate(userID, deckID uint) time.Time {
+	db := database.DBConn
+
+	access := new(models.Access)
+	if err := db.Select("exam_date").Where("accesses.user_id = ? AND accesses.deck_id = ?", userID, deckID).First(&access).Error; err != nil {
+		return time.Time{}
+	}
+
+	return access.ExamDate
+}
+
+// CramInterval compresses an interval so the card is seen again before the exam day
+// Intervals are left untouched without exam date, on the exam day and once it has passed
+func CramInterval(interval uint, user *models.User, examDate, now time.Time) uint {
+	if examDate.IsZero() {
+		return interval
+	}
+
+	today := user.GetDayStart(now)
+
+	daysLeft := int(ExamDayStart(user, examDate).Sub(today).Hours()/24 + 0.5)
+	if daysLeft <= 0 {
+		return interval
+	}
+
+	// The day before the exam at the latest, the exam day itself if it's tomorrow
+	limit := uint(1)
+	if daysLeft > 1 {
+		limit = uint(daysLeft - 1)
+	}
+
+	if interval > limit {
+		return limit
+	}
+
+	return interval
+}
+
+// CramNextDate returns when a card due at nextDate has to be reviewed to be seen again before the exam day
+// The card is pulled back like CramInterval would have scheduled it, otherwise nextDate is returned
+func CramNextDate(nextDate time.Time, user *models.User, examDate, now time.Time) time.Time {
+	today := user.GetDayStart(now)
+
+	days := int(nextDate.Sub(today).Hours()/24 + 0.5)
+	if days <= 0 {
+		return nextDate
+	}
+
+	if interval := CramInterval(uint(days), user, examDate, now); interval < uint(days) {
+		return today.AddDate(0, 0, int(interval))
+	}
+
+	return nextDate
+}
+
+// ExamDayStart returns the start of the exam day in the user timezone
+// The calendar day is taken in the user timezone, whatever the location the database returned examDate in
+func ExamDayStart(user *models.User, examDate time.Time) time.Time {
+	location := user.GetLocation()
+	examDate = examDate.In(location)
+	return time.Date(examDate.Year(), examDate.Month(), examDate.Day(), int(user.DayStart), 0, 0, 0, location)
+}
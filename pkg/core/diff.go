@@ -0,0 +1,177 @@
+package core
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/memnix/memnixrest/app/models"
+)
+
+// DiffAnswer compares a response to the closest accepted answer
+// It returns the token diff and the kinds of errors it contains
+func DiffAnswer(response string, card *models.Card, answers []models.Answer) ([]models.DiffPart, []models.AnswerError) {
+	closest := closestAnswer(response, card, answers)
+
+	given, expected := strings.Fields(response), strings.Fields(closest)
+	parts := diffTokens(given, expected, card)
+
+	answerErrors := make([]models.AnswerError, 0)
+	seen := make(map[models.AnswerError]bool)
+	addError := func(answerError models.AnswerError) {
+		if !seen[answerError] {
+			seen[answerError] = true
+			answerErrors = append(answerErrors, answerError)
+		}
+	}
+
+	if len(given) > 1 && sameTokens(given, expected, card) {
+		addError(models.AnswerErrorTransposition)
+	}
+
+	for i := range parts {
+		switch parts[i].Op {
+		case models.DiffMissing:
+			if !seen[models.AnswerErrorTransposition] {
+				addError(models.AnswerErrorMissingWord)
+			}
+		case models.DiffExtra:
+			if !seen[models.AnswerErrorTransposition] {
+				addError(models.AnswerErrorExtraWord)
+			}
+		case models.DiffReplace:
+			addError(classifyToken(parts[i].Text, parts[i].Expected, card))
+		}
+	}
+
+	return parts, answerErrors
+}
+
+// closestAnswer returns the accepted answer with the smallest edit distance to the response
+func closestAnswer(response string, card *models.Card, answers []models.Answer) string {
+	closest := card.Answer
+	respRunes := []rune(normalizeAnswer(response, card))
+	best := levenshtein(respRunes, []rune(normalizeAnswer(closest, card)))
+
+	for i := range answers {
+		if distance := levenshtein(respRunes, []rune(normalizeAnswer(answers[i].Answer, card))); distance < best {
+			closest, best = answers[i].Answer, distance
+		}
+	}
+
+	return closest
+}
+
+// diffTokens aligns the response and answer tokens on their longest common subsequence
+// Consecutive extra and missing tokens are paired as replacements
+func diffTokens(given, expected []string, card *models.Card) []models.DiffPart {
+	equal := func(a, b string) bool {
+		return normalizeAnswer(a, card) == normalizeAnswer(b, card)
+	}
+
+	lcs := make([][]int, len(given)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(expected)+1)
+	}
+	for i := len(given) - 1; i >= 0; i-- {
+		for j := len(expected) - 1; j >= 0; j-- {
+			if equal(given[i], expected[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	parts := make([]models.DiffPart, 0, len(given)+len(expected))
+	var extra, missing []string
+
+	flush := func() {
+		for len(extra) > 0 && len(missing) > 0 {
+			parts = append(parts, models.DiffPart{Op: models.DiffReplace, Text: extra[0], Expected: missing[0]})
+			extra, missing = extra[1:], missing[1:]
+		}
+		for _, token := range extra {
+			parts = append(parts, models.DiffPart{Op: models.DiffExtra, Text: token})
+		}
+		for _, token := range missing {
+			parts = append(parts, models.DiffPart{Op: models.DiffMissing, Expected: token})
+		}
+		extra, missing = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(given) || j < len(expected) {
+		switch {
+		case i < len(given) && j < len(expected) && equal(given[i], expected[j]):
+			flush()
+			parts = append(parts, models.DiffPart{Op: models.DiffEqual, Text: given[i], Expected: expected[j]})
+			i++
+			j++
+		case j == len(expected) || (i < len(given) && lcs[i+1][j] >= lcs[i][j+1]):
+			extra = append(extra, given[i])
+			i++
+		default:
+			missing = append(missing, expected[j])
+			j++
+		}
+	}
+	flush()
+
+	return parts
+}
+
+// sameTokens returns if the response has the answer tokens in another order
+func sameTokens(given, expected []string, card *models.Card) bool {
+	if len(given) != len(expected) {
+		return false
+	}
+
+	normalize := func(tokens []string) []string {
+		normalized := make([]string, len(tokens))
+		for i := range tokens {
+			normalized[i] = normalizeAnswer(tokens[i], card)
+		}
+		sort.Strings(normalized)
+		return normalized
+	}
+
+	a, b := normalize(given), normalize(expected)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// classifyToken returns the kind of error between a response token and the answer token it replaces
+func classifyToken(given, expected string, card *models.Card) models.AnswerError {
+	a, b := []rune(strings.ToLower(given)), []rune(strings.ToLower(expected))
+
+	switch {
+	case foldAccents(string(a)) == foldAccents(string(b)):
+		return models.AnswerErrorAccent
+	case isSwap(a, b):
+		return models.AnswerErrorTransposition
+	case levenshtein(a, b) <= maxTypos(len(b)):
+		return models.AnswerErrorTypo
+	default:
+		return models.AnswerErrorWrongWord
+	}
+}
+
+// isSwap returns if two words only differ by two adjacent characters swapped
+func isSwap(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := 0; i+1 < len(a); i++ {
+		if a[i] != b[i] {
+			return a[i] == b[i+1] && a[i+1] == b[i] && string(a[i+2:]) == string(b[i+2:])
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/utils"
+)
+
+// SelectDistractors picks count-1 wrong options and the answer, then shuffles them
+// Options the user confused with the answer come first, then options similar to the answer
+// Experienced learners never get an option below utils.MinDistractorSimilarity unless they confused it before
+// Returns an empty list when there aren't enough distractors, so the card falls back to a typed answer
+func SelectDistractors(options []models.McqOption, answer string, count int, confusions map[string]int, experienced bool) []models.McqOption {
+	correct := models.McqOption{Answer: answer}
+	seen := map[string]bool{answer: true}
+	distractors := make([]models.McqOption, 0, len(options))
+	scores := make(map[string]float64, len(options))
+
+	rand.Seed(time.Now().UnixNano())
+
+	for i := range options {
+		if options[i].Answer == answer && correct.ID == 0 {
+			correct = options[i]
+		}
+		if seen[options[i].Answer] {
+			continue
+		}
+		seen[options[i].Answer] = true
+
+		similarity := DistractorSimilarity(answer, options[i].Answer)
+		confused := confusions[options[i].Answer]
+		if experienced && confused == 0 && similarity < utils.MinDistractorSimilarity {
+			continue // Too easy
+		}
+
+		scores[options[i].Answer] = float64(confused)*utils.DistractorConfusionWeight + similarity + rand.Float64()*utils.DistractorJitter
+		distractors = append(distractors, options[i])
+	}
+
+	if count < 2 || len(distractors) < count-1 {
+		return make([]models.McqOption, 0)
+	}
+
+	sort.SliceStable(distractors, func(i, j int) bool {
+		return scores[distractors[i].Answer] > scores[distractors[j].Answer]
+	})
+
+	result := append(distractors[:count-1], correct)
+
+	rand.Shuffle(len(result), func(i, j int) { result[i], result[j] = result[j], result[i] })
+
+	return result
+}
+
+// DistractorSimilarity returns how close an option looks to the answer, from 0 to 1
+// It compares the length, the number of words and the shape of both strings
+func DistractorSimilarity(answer, option string) float64 {
+	answerRunes, optionRunes := []rune(strings.TrimSpace(answer)), []rune(strings.TrimSpace(option))
+	if len(answerRunes) == 0 || len(optionRunes) == 0 {
+		return 0
+	}
+
+	length := ratio(len(answerRunes), len(optionRunes))
+	words := ratio(len(strings.Fields(answer)), len(strings.Fields(option)))
+
+	answerShape, optionShape := []rune(shapeOf(answerRunes)), []rune(shapeOf(optionRunes))
+	shape := 1 - float64(levenshtein(answerShape, optionShape))/float64(max(len(answerShape), len(optionShape)))
+
+	return 0.3*length + 0.2*words + 0.5*shape
+}
+
+// shapeOf maps digits to 9, uppercase letters to A, other letters to a and collapses runs
+// "Louis XIV" and "Henri IV" both have the shape "Aa A"
+func shapeOf(value []rune) string {
+	var shape strings.Builder
+	var last rune
+
+	for _, r := range value {
+		switch {
+		case unicode.IsDigit(r):
+			r = '9'
+		case unicode.IsUpper(r):
+			r = 'A'
+		case unicode.IsLetter(r):
+			r = 'a'
+		case unicode.IsSpace(r):
+			r = ' '
+		}
+		if r != last {
+			shape.WriteRune(r)
+			last = r
+		}
+	}
+
+	return shape.String()
+}
+
+func ratio(a, b int) float64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return float64(min(a, b)) / float64(max(a, b))
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
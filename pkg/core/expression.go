@@ -0,0 +1,22 @@
+package core
+
+import (
+	"github.com/memnix/memnixrest/pkg/utils"
+)
+
+// validateExpression checks a math expression response against the accepted answers of a CardExpression
+func validateExpression(response string, accepted []string) (string, AnswerMatch) {
+	expression, ok := utils.ParseExpression(response)
+	if !ok {
+		return "", MatchNone
+	}
+
+	for i := range accepted {
+		expected, ok := utils.ParseExpression(accepted[i])
+		if ok && utils.EquivalentExpressions(expected, expression) {
+			return accepted[i], MatchExact
+		}
+	}
+
+	return "", MatchNone
+}
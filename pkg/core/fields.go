@@ -0,0 +1,57 @@
+package core
+
+import (
+	"github.com/memnix/memnixrest/app/models"
+)
+
+// ValidateFields checks each field of a multi-field response with the matching rules of its field type
+// It returns the result of each field and the share of correct fields, near-misses counting as half
+func ValidateFields(response []string, card *models.Card, answers []models.Answer) ([]models.FieldValidation, float64) {
+	fields := card.GetFields()
+	results := make([]models.FieldValidation, len(fields))
+
+	alternatives := make([][]string, 0, len(answers))
+	for i := range answers {
+		if split := models.SplitFields(answers[i].Answer); len(split) == len(fields) {
+			alternatives = append(alternatives, split)
+		}
+	}
+
+	expected := models.SplitFields(card.Answer)
+	score := 0.0
+
+	for i := range fields {
+		fieldCard := *card
+		fieldCard.Type = card.GetFieldType(i)
+		fieldCard.Answer = expected[i]
+
+		fieldAnswers := make([]models.Answer, len(alternatives))
+		for j := range alternatives {
+			fieldAnswers[j].Answer = alternatives[j][i]
+		}
+
+		given := ""
+		if i < len(response) {
+			given = response[i]
+		}
+
+		matched, match := ValidateAnswer(given, &fieldCard, fieldAnswers)
+
+		results[i] = models.FieldValidation{
+			Field:    fields[i],
+			Validate: match != MatchNone,
+			Almost:   match == MatchAlmost,
+			Answer:   expected[i],
+			Matched:  matched,
+		}
+
+		switch match {
+		case MatchExact:
+			score++
+		case MatchAlmost:
+			score += 0.5
+		}
+	}
+
+	return results, score / float64(len(fields))
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"math"
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+)
+
+// FSRS grades
+const (
+	fsrsAgain = iota + 1
+	fsrsHard
+	fsrsGood
+	fsrsEasy
+)
+
+const fsrsMaxInterval = 36500
+
+// FSRSScheduler is a Scheduler based on the Free Spaced Repetition Scheduler (v4) algorithm.
+// It stores its state in models.Mem Stability and Difficulty.
+type FSRSScheduler struct {
+	Weights          [17]float64
+	RequestRetention float64
+}
+
+// NewFSRSScheduler returns a FSRSScheduler with the default weights
+func NewFSRSScheduler() *FSRSScheduler {
+	return &FSRSScheduler{
+		Weights:          [17]float64{0.4, 0.6, 2.4, 5.8, 4.93, 0.94, 0.86, 0.01, 1.49, 0.14, 0.94, 2.18, 0.05, 0.34, 1.26, 0.29, 2.61},
+		RequestRetention: 0.9,
+	}
+}
+
+// Review computes FSRS values
+func (s *FSRSScheduler) Review(last, mem *models.Mem, validation bool) {
+	s.review(last, mem, s.gradeFromQuality(last.Quality, validation))
+}
+
+// Training keeps the FSRS state and only updates the efactor used for MCQ progression
+func (*FSRSScheduler) Training(last, mem *models.Mem, _ bool) {
+	mem.Efactor = computeTrainingEfactor(last.Efactor, last.Quality)
+	mem.Interval, mem.Repetition, mem.LearningStage = last.Interval, last.Repetition, last.LearningStage
+	mem.Stability, mem.Difficulty = last.Stability, last.Difficulty
+}
+
+// SelfEvaluated computes FSRS values using the self evaluated quality as grade
+func (s *FSRSScheduler) SelfEvaluated(last, mem *models.Mem, quality models.MemQuality, training bool) {
+	if training {
+		s.Training(last, mem, quality > fsrsAgain)
+		return
+	}
+
+	grade := int(quality)
+	if grade < fsrsAgain {
+		grade = fsrsAgain
+	} else if grade > fsrsEasy {
+		grade = fsrsEasy
+	}
+
+	s.review(last, mem, grade)
+}
+
+// review applies a grade to the FSRS state
+func (s *FSRSScheduler) review(last, mem *models.Mem, grade int) {
+	w := s.Weights
+
+	if last.Stability == 0 {
+		mem.Stability = float32(w[grade-1])
+		mem.Difficulty = float32(s.initDifficulty(grade))
+	} else {
+		stability, difficulty := float64(last.Stability), float64(last.Difficulty)
+		retrievability := math.Pow(1+s.elapsedDays(last)/(9*stability), -1)
+
+		if grade == fsrsAgain {
+			mem.Stability = float32(w[11] * math.Pow(difficulty, -w[12]) * (math.Pow(stability+1, w[13]) - 1) * math.Exp(w[14]*(1-retrievability)))
+		} else {
+			hardPenalty, easyBonus := 1.0, 1.0
+			if grade == fsrsHard {
+				hardPenalty = w[15]
+			} else if grade == fsrsEasy {
+				easyBonus = w[16]
+			}
+			mem.Stability = float32(stability * (math.Exp(w[8])*(11-difficulty)*math.Pow(stability, -w[9])*(math.Exp(w[10]*(1-retrievability))-1)*hardPenalty*easyBonus + 1))
+		}
+
+		difficulty -= w[6] * float64(grade-fsrsGood)
+		mem.Difficulty = float32(s.clampDifficulty(w[7]*s.initDifficulty(fsrsGood) + (1-w[7])*difficulty))
+	}
+
+	if grade == fsrsAgain {
+		mem.Repetition = 0
+		mem.Interval = 0
+		mem.LearningStage = models.StageToLearn
+	} else {
+		mem.Repetition = last.Repetition + 1
+		mem.Interval = s.nextInterval(float64(mem.Stability))
+		mem.LearningStage = new(SM2Scheduler).computeLearningStage(mem.Repetition)
+	}
+
+	// Efactor still drives the MCQ progression (see models.Mem.IsMCQ)
+	mem.Efactor = computeEfactor(last.Efactor, last.Quality)
+}
+
+// gradeFromQuality converts a computed models.MemQuality to a FSRS grade
+func (*FSRSScheduler) gradeFromQuality(quality models.MemQuality, validation bool) int {
+	switch {
+	case !validation:
+		return fsrsAgain
+	case quality >= models.MemQualityPerfect:
+		return fsrsEasy
+	case quality >= models.MemQualityGoodMCQ:
+		return fsrsGood
+	default:
+		return fsrsHard
+	}
+}
+
+func (s *FSRSScheduler) initDifficulty(grade int) float64 {
+	return s.clampDifficulty(s.Weights[4] - float64(grade-fsrsGood)*s.Weights[5])
+}
+
+func (*FSRSScheduler) clampDifficulty(difficulty float64) float64 {
+	return math.Min(math.Max(difficulty, 1), 10)
+}
+
+func (s *FSRSScheduler) nextInterval(stability float64) uint {
+	interval := math.Round(9 * stability * (1/s.RequestRetention - 1))
+	return uint(math.Min(math.Max(interval, 1), fsrsMaxInterval))
+}
+
+// elapsedDays returns the number of days since the last review
+func (*FSRSScheduler) elapsedDays(last *models.Mem) float64 {
+	if last.CreatedAt.IsZero() {
+		return 0
+	}
+	return math.Max(time.Since(last.CreatedAt).Hours()/24, 0)
+}
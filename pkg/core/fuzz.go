@@ -0,0 +1,109 @@
+package core
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/database"
+)
+
+// FuzzRange returns the allowed interval window around an interval
+func FuzzRange(interval uint) (uint, uint) {
+	var fuzz uint
+
+	switch {
+	case interval < 2:
+		return interval, interval
+	case interval == 2:
+		return 2, 3
+	case interval < 7:
+		fuzz = interval / 4
+	case interval < 30:
+		fuzz = interval * 15 / 100
+		if fuzz < 2 {
+			fuzz = 2
+		}
+	default:
+		fuzz = interval * 5 / 100
+		if fuzz < 4 {
+			fuzz = 4
+		}
+	}
+
+	if fuzz < 1 {
+		fuzz = 1
+	}
+
+	return interval - fuzz, interval + fuzz
+}
+
+// FuzzInterval returns a fuzzed interval within FuzzRange
+// The fuzz is deterministic for a given user, card, cloze and repetition
+func FuzzInterval(interval uint, mem *models.Mem) uint {
+	minInterval, maxInterval := FuzzRange(interval)
+	if minInterval == maxInterval {
+		return interval
+	}
+
+	hash := fnv.New32a()
+	buffer := make([]byte, 8)
+	for _, value := range []uint64{uint64(mem.UserID), uint64(mem.CardID), uint64(mem.Cloze), uint64(mem.Repetition)} {
+		binary.LittleEndian.PutUint64(buffer, value)
+		_, _ = hash.Write(buffer)
+	}
+
+	return minInterval + uint(hash.Sum32())%(maxInterval-minInterval+1)
+}
+
+// BalanceInterval returns the interval within FuzzRange with the fewest reviews already due for the user
+// Ties are broken by the distance to the fuzzed interval
+func BalanceInterval(interval uint, mem *models.Mem, user *models.User) uint {
+	db := database.DBConn
+
+	fuzzed := FuzzInterval(interval, mem)
+	minInterval, maxInterval := FuzzRange(interval)
+	if minInterval == maxInterval {
+		return fuzzed
+	}
+
+	today := user.GetDayStart(time.Now())
+
+	var dates []time.Time
+	if err := db.Model(&models.MemDate{}).Where("mem_dates.user_id = ? AND mem_dates.next_date >= ? AND mem_dates.next_date < ?",
+		user.ID, today.AddDate(0, 0, int(minInterval)), today.AddDate(0, 0, int(maxInterval)+1)).Pluck("next_date", &dates).Error; err != nil {
+		return fuzzed
+	}
+
+	load := make(map[uint]int)
+	for i := range dates {
+		load[uint(user.GetDayStart(dates[i]).Sub(today).Hours()/24+0.5)]++
+	}
+
+	best := fuzzed
+	for candidate := minInterval; candidate <= maxInterval; candidate++ {
+		if load[candidate] < load[best] || (load[candidate] == load[best] && distance(candidate, fuzzed) < distance(best, fuzzed)) {
+			best = candidate
+		}
+	}
+
+	return best
+}
+
+// ScheduleInterval returns the interval to use for the next review date
+// It applies fuzz and, if the user enabled it, load balancing
+func ScheduleInterval(mem *models.Mem, user *models.User) uint {
+	if user.LoadBalance {
+		return BalanceInterval(mem.Interval, mem, user)
+	}
+
+	return FuzzInterval(mem.Interval, mem)
+}
+
+func distance(a, b uint) uint {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
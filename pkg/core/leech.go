@@ -0,0 +1,21 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/memnix/memnixrest/app/models"
+)
+
+// UpdateLeech counts a lapse on memDate and publishes a LogCardLeech event when the card becomes a leech
+// It returns true if the card just became a leech
+func UpdateLeech(memDate *models.MemDate) bool {
+	if !memDate.AddLapse(&memDate.Deck) {
+		return false
+	}
+
+	log := models.CreateLog(fmt.Sprintf("Card %d became a leech for %s after %d lapses (suspended: %t)", memDate.CardID, memDate.User.Email,
+		memDate.Lapses, memDate.Suspended), models.LogCardLeech).SetType(models.LogTypeInfo).AttachIDs(memDate.UserID, memDate.DeckID, memDate.CardID)
+	_ = log.SendLog()
+
+	return true
+}
@@ -3,112 +3,155 @@ package core
 import (
 	"github.com/memnix/memnixrest/app/models"
 	"github.com/memnix/memnixrest/pkg/database"
-	"strings"
+	"gorm.io/gorm"
+	"time"
 )
 
 // UpdateMemSelfEvaluated computes self evaluated mem
-func UpdateMemSelfEvaluated(r *models.Mem, training bool, quality uint) {
+// Out of training, the card is scheduled like PreviewSelfEvaluation announced it
+func UpdateMemSelfEvaluated(r *models.Mem, memDate *models.MemDate, training bool, grade uint) {
 	db := database.DBConn
 
 	mem := new(models.Mem)
 
-	mem.UserID, mem.CardID = r.UserID, r.CardID
+	mem.UserID, mem.CardID, mem.Cloze = r.UserID, r.CardID, r.Cloze
 
 	mem.Quality = models.MemQualityNone
-	r.Quality = models.MemQuality(quality)
+	mem.SetPrevious(memDate)
+	r.ComputeQualitySelfEvaluated(grade)
+	r.Training = training
 
-	if training {
-		mem.ComputeTrainingEfactor(r.Efactor, r.Quality)
-	} else {
-		mem.ComputeEfactor(r.Efactor, r.Quality)
+	step, inStep := selfEvaluate(r, mem, memDate, training, grade)
+	if !training && r.IsLapse() {
+		r.Leeched = UpdateLeech(memDate)
+	}
+	if !training && !inStep {
+		mem.Interval = ScheduleInterval(mem, &memDate.User)
 	}
-
-	mem.Interval, mem.Repetition = r.Interval, r.Repetition
 
 	db.Save(r)
 	db.Create(mem)
+
+	if !training {
+		_ = UpdateMemDate(db, mem, memDate, step, inStep)
+	}
 }
 
-// UpdateMemDate computes NextDate and set it
-func UpdateMemDate(mem *models.Mem) {
-	db := database.DBConn
-	memDate := new(models.MemDate)
+// PreviewSelfEvaluation returns the next review of each self evaluation grade without saving anything
+// Load balancing isn't applied, so the date may be a day off for users who enabled it
+func PreviewSelfEvaluation(last *models.Mem, memDate *models.MemDate, examDate time.Time) []models.IntervalPreview {
+	previews := make([]models.IntervalPreview, 0, fsrsEasy)
 
-	_ = db.Joins("Card").Joins("User").Joins("Deck").Where("mem_dates.user_id = ? AND mem_dates.card_id = ?",
-		mem.UserID, mem.CardID).First(&memDate).Error
-	//TODO: Error handling
+	for grade := fsrsAgain; grade <= fsrsEasy; grade++ {
+		r, next := *last, *memDate
+		mem := &models.Mem{UserID: r.UserID, CardID: r.CardID, Cloze: r.Cloze}
+		r.ComputeQualitySelfEvaluated(uint(grade))
 
-	memDate.ComputeNextDate(int(mem.Interval))
+		preview := models.IntervalPreview{Quality: models.MemQuality(grade)}
 
-	db.Save(memDate)
+		step, inStep := selfEvaluate(&r, mem, &next, false, uint(grade))
+		if inStep {
+			next.ComputeNextStep(step)
+		} else {
+			preview.Interval = CramInterval(FuzzInterval(mem.Interval, mem), &next.User, examDate, time.Now())
+			next.ComputeNextDate(int(preview.Interval))
+		}
+		preview.NextDate = next.NextDate
 
-	//TODO: Return error
-}
+		previews = append(previews, preview)
+	}
 
-// UpdateMemTraining computes and set mem values
-func UpdateMemTraining(r *models.Mem, validation bool) {
-	db := database.DBConn
+	return previews
+}
 
-	mem := new(models.Mem)
+// selfEvaluate fills mem from the self evaluation grade and applies the deck learning steps
+// It returns the delay before the next review and true if mem is in a learning step
+func selfEvaluate(r, mem *models.Mem, memDate *models.MemDate, training bool, grade uint) (time.Duration, bool) {
+	GetScheduler(memDate.Deck.Scheduler).SelfEvaluated(r, mem, models.MemQuality(grade), training)
+	if training {
+		return 0, false
+	}
 
-	mem.UserID, mem.CardID = r.UserID, r.CardID
+	return ComputeLearningStep(r, mem, r.IsSuccess(), &memDate.Deck)
+}
 
-	if validation {
-		r.ComputeQualitySuccess()
+// UpdateMemDate computes NextDate and set it
+// If mem is in a learning step, NextDate is set after the step delay
+// Before an exam date, NextDate is compressed but mem.Interval is kept so scheduling resumes after the exam
+func UpdateMemDate(db *gorm.DB, mem *models.Mem, memDate *models.MemDate, step time.Duration, inStep bool) error {
+	if inStep {
+		memDate.ComputeNextStep(step)
 	} else {
-		r.ComputeQualityFail()
+		examDate := FetchExamDate(memDate.UserID, memDate.DeckID)
+		memDate.ComputeNextDate(int(CramInterval(mem.Interval, &memDate.User, examDate, time.Now())))
 	}
+	memDate.LearningStage = mem.LearningStage
+
+	return db.Save(memDate).Error
+}
+
+// UpdateMemTraining computes and set mem values
+func UpdateMemTraining(db *gorm.DB, r *models.Mem, memDate *models.MemDate, validation *models.CardResponseValidation) error {
+	mem := new(models.Mem)
+
+	mem.UserID, mem.CardID, mem.Cloze = r.UserID, r.CardID, r.Cloze
+
+	computeQuality(r, validation, &memDate.Deck)
 
 	mem.Quality = models.MemQualityNone
+	mem.SetPrevious(memDate)
+	r.Training = true
 
-	mem.ComputeTrainingEfactor(r.Efactor, r.Quality)
-	mem.Interval, mem.Repetition = r.Interval, r.Repetition
+	GetScheduler(memDate.Deck.Scheduler).Training(r, mem, validation.Validate)
 
-	db.Save(r)
-	db.Create(mem)
+	if err := db.Save(r).Error; err != nil {
+		return err
+	}
+
+	return db.Create(mem).Error
 }
 
 // UpdateMem computes and set mem values
-func UpdateMem(r *models.Mem, validation bool) {
-	db := database.DBConn
-
+func UpdateMem(db *gorm.DB, r *models.Mem, memDate *models.MemDate, validation *models.CardResponseValidation) error {
 	mem := new(models.Mem)
 
-	mem.UserID, mem.CardID = r.UserID, r.CardID
+	mem.UserID, mem.CardID, mem.Cloze = r.UserID, r.CardID, r.Cloze
 
-	if validation {
-		mem.ComputeInterval(r.Interval, r.Efactor, r.Repetition)
-		mem.Repetition = r.Repetition + 1
-		mem.ComputeLearningStage()
-		r.ComputeQualitySuccess()
-	} else {
-		mem.Repetition = 0
-		mem.Interval = 0
-		mem.LearningStage = models.StageToLearn
-		r.ComputeQualityFail()
-	}
+	computeQuality(r, validation, &memDate.Deck)
 
 	mem.Quality = models.MemQualityNone
+	mem.SetPrevious(memDate)
 
-	mem.ComputeEfactor(r.Efactor, r.Quality)
+	GetScheduler(memDate.Deck.Scheduler).Review(r, mem, validation.Validate)
+	if r.IsLapse() {
+		r.Leeched = UpdateLeech(memDate)
+	}
+	step, inStep := ComputeLearningStep(r, mem, validation.Validate, &memDate.Deck)
+	if !inStep {
+		mem.Interval = ScheduleInterval(mem, &memDate.User)
+	}
 
-	db.Save(r)
-	db.Create(mem)
+	if err := db.Save(r).Error; err != nil {
+		return err
+	}
+
+	if err := db.Create(mem).Error; err != nil {
+		return err
+	}
 
-	UpdateMemDate(mem)
+	return UpdateMemDate(db, mem, memDate, step, inStep)
 }
 
-func ValidateAnswer(response string, card *models.Card) bool {
-	var respString, answerString string
-	if card.Spaces {
-		respString = strings.Join(strings.Fields(response), " ")
-		answerString = strings.Join(strings.Fields(card.Answer), " ")
-	} else {
-		respString = strings.ReplaceAll(response, " ", "")
-		answerString = strings.ReplaceAll(card.Answer, " ", "")
-	}
-	if card.Case {
-		return strings.Compare(respString, answerString) == 0
+// computeQuality sets the answer Quality from the response validation
+func computeQuality(r *models.Mem, validation *models.CardResponseValidation, deck *models.Deck) {
+	switch {
+	case validation.Almost:
+		r.ComputeQualityAlmost()
+	case validation.Validate:
+		r.ComputeQualitySuccess(deck)
+	case validation.Partial:
+		r.ComputeQualityPartial(validation.Score)
+	default:
+		r.ComputeQualityFail(deck)
 	}
-	return strings.EqualFold(respString, answerString)
 }
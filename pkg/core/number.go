@@ -0,0 +1,54 @@
+package core
+
+import (
+	"math"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/utils"
+)
+
+// validateNumber checks a numeric response against the accepted answers of a CardInt
+// The response may omit the unit of the answer and, if Card.AcceptUnits is set, use another unit of the same dimension
+func validateNumber(response string, card *models.Card, accepted []string) (string, AnswerMatch) {
+	quantity, ok := utils.ParseQuantity(response, card.Deck.Lang)
+	if !ok {
+		return "", MatchNone
+	}
+
+	for i := range accepted {
+		expected, ok := utils.ParseQuantity(accepted[i], card.Deck.Lang)
+		if !ok {
+			continue
+		}
+
+		given := quantity
+		if given.Unit == "" {
+			given.Unit = expected.Unit
+		}
+		if given.Unit != expected.Unit && !card.AcceptUnits {
+			continue
+		}
+
+		value, ok := given.Convert(expected.Unit)
+		if !ok {
+			continue
+		}
+
+		if math.Abs(value-expected.Value) <= numberTolerance(expected.Value, card) {
+			return accepted[i], MatchExact
+		}
+	}
+
+	return "", MatchNone
+}
+
+// numberTolerance returns the absolute tolerance allowed around an expected value
+func numberTolerance(expected float64, card *models.Card) float64 {
+	tolerance := card.Tolerance
+	if card.RelativeTolerance {
+		tolerance *= math.Abs(expected)
+	}
+
+	// Absorb floating point errors from unit conversions
+	return tolerance + 1e-9*math.Max(1, math.Abs(expected))
+}
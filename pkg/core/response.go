@@ -0,0 +1,54 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/memnix/memnixrest/app/models"
+)
+
+// ValidateResponse checks a response with the card matching policy
+// A cloze card is checked against its hidden span only: Card.Answer is set to the cloze answer
+// A card asked as a mcq only accepts the exact correct option, so look-alike distractors are never almost correct
+func ValidateResponse(response *models.CardResponse, card *models.Card, answers []models.Answer, mcq bool) *models.CardResponseValidation {
+	validation := new(models.CardResponseValidation)
+
+	if mcq {
+		validation.SetIncorrect()
+		if strings.TrimSpace(response.Response) == strings.TrimSpace(card.Answer) {
+			validation.SetCorrect()
+			validation.Matched = card.Answer
+		}
+		return validation
+	}
+
+	if card.Type == models.CardCloze {
+		card.Answer = card.GetClozeAnswer(response.Cloze)
+		answers = nil
+	}
+
+	if len(card.GetFields()) != 0 {
+		fields := response.Fields
+		if len(fields) == 0 {
+			fields = models.SplitFields(response.Response)
+		}
+		validation.SetFields(ValidateFields(fields, card, answers))
+		return validation
+	}
+
+	matched, match := ValidateAnswer(response.Response, card, answers)
+	switch match {
+	case MatchExact:
+		validation.SetCorrect()
+	case MatchAlmost:
+		validation.SetAlmost()
+	default:
+		validation.SetIncorrect()
+	}
+	validation.Matched = matched
+
+	if match != MatchExact && (card.Type == models.CardString || card.Type == models.CardCloze) {
+		validation.Diff, validation.Errors = DiffAnswer(response.Response, card, answers)
+	}
+
+	return validation
+}
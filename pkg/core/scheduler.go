@@ -0,0 +1,38 @@
+package core
+
+import (
+	"github.com/memnix/memnixrest/app/models"
+)
+
+// Scheduler computes the next review state of a card.
+// last is the latest Mem of the user on the card and mem is the new Mem to fill.
+type Scheduler interface {
+	// Review fills mem after a graded answer
+	Review(last, mem *models.Mem, validation bool)
+	// Training fills mem after a training answer. It must not move the card in time
+	Training(last, mem *models.Mem, validation bool)
+	// SelfEvaluated fills mem after a self evaluated answer, quality being the grade from Again (1) to Easy (4)
+	SelfEvaluated(last, mem *models.Mem, quality models.MemQuality, training bool)
+}
+
+var schedulers = make(map[models.SchedulerType]Scheduler)
+
+func init() {
+	RegisterScheduler(models.SchedulerSM2, new(SM2Scheduler))
+	RegisterScheduler(models.SchedulerFSRS, NewFSRSScheduler())
+}
+
+// RegisterScheduler makes a Scheduler available for a given models.SchedulerType
+func RegisterScheduler(schedulerType models.SchedulerType, scheduler Scheduler) {
+	schedulers[schedulerType] = scheduler
+}
+
+// GetScheduler returns the Scheduler registered for a given models.SchedulerType
+// It falls back to SM-2 if the type isn't registered
+func GetScheduler(schedulerType models.SchedulerType) Scheduler {
+	if scheduler, ok := schedulers[schedulerType]; ok {
+		return scheduler
+	}
+
+	return schedulers[models.SchedulerSM2]
+}
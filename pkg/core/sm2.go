@@ -0,0 +1,113 @@
+package core
+
+import (
+	"math"
+
+	"github.com/memnix/memnixrest/app/models"
+)
+
+const (
+	sm2HardFactor = 0.8 // Interval factor of a self evaluated Hard
+	sm2EasyBonus  = 1.3 // Interval factor of a self evaluated Easy
+)
+
+// SM2Scheduler is the default Scheduler based on the SuperMemo 2 algorithm
+type SM2Scheduler struct{}
+
+// Review computes SM-2 values
+func (s *SM2Scheduler) Review(last, mem *models.Mem, validation bool) {
+	if validation {
+		mem.Interval = s.computeInterval(last.Interval, last.Efactor, last.Repetition)
+		mem.Repetition = last.Repetition + 1
+		mem.LearningStage = s.computeLearningStage(mem.Repetition)
+	} else {
+		mem.Repetition = 0
+		mem.Interval = 0
+		mem.LearningStage = models.StageToLearn
+	}
+
+	mem.Efactor = computeEfactor(last.Efactor, last.Quality)
+}
+
+// Training computes SM-2 training values
+func (*SM2Scheduler) Training(last, mem *models.Mem, _ bool) {
+	mem.Efactor = computeTrainingEfactor(last.Efactor, last.Quality)
+	mem.Interval, mem.Repetition = last.Interval, last.Repetition
+}
+
+// SelfEvaluated computes SM-2 values from a self evaluated quality
+// Out of training, the quality is read as a grade from Again to Easy
+func (s *SM2Scheduler) SelfEvaluated(last, mem *models.Mem, quality models.MemQuality, training bool) {
+	if training {
+		mem.Efactor = computeTrainingEfactor(last.Efactor, quality)
+		mem.Interval, mem.Repetition = last.Interval, last.Repetition
+		return
+	}
+
+	mem.Efactor = computeEfactor(last.Efactor, quality)
+
+	if int(quality) <= fsrsAgain {
+		mem.Repetition = 0
+		mem.Interval = 0
+		mem.LearningStage = models.StageToLearn
+		return
+	}
+
+	interval := s.computeInterval(last.Interval, mem.Efactor, last.Repetition)
+	switch int(quality) {
+	case fsrsHard:
+		interval = uint(math.Max(math.Round(float64(interval)*sm2HardFactor), 1))
+	case fsrsEasy:
+		interval = uint(math.Max(math.Round(float64(interval)*sm2EasyBonus), float64(interval+1)))
+	}
+
+	mem.Interval = interval
+	mem.Repetition = last.Repetition + 1
+	mem.LearningStage = s.computeLearningStage(mem.Repetition)
+}
+
+// computeInterval returns the interval between reviews
+func (*SM2Scheduler) computeInterval(oldInterval uint, eFactor float32, repetition uint) uint {
+	switch repetition {
+	case 0:
+		return 1
+	case 1, 2:
+		return 2
+	case 3:
+		return 3
+	default:
+		return uint(float32(oldInterval)*eFactor*0.75) + 1
+	}
+}
+
+// computeLearningStage returns the models.LearningStage matching a repetition count
+func (*SM2Scheduler) computeLearningStage(repetition uint) models.LearningStage {
+	switch {
+	case repetition > 3 && repetition < 7:
+		return models.StageReviewing
+	case repetition > 7:
+		return models.StageKnown
+	default:
+		return models.StageLearning
+	}
+}
+
+// computeEfactor returns the new efactor using oldEfactor and MemQuality
+func computeEfactor(oldEfactor float32, quality models.MemQuality) float32 {
+	eFactor := oldEfactor + (0.1 - (5.0-float32(quality))*(0.08+(5-float32(quality)))*0.02)
+
+	if eFactor < 1.3 {
+		return 1.3
+	}
+	return eFactor
+}
+
+// computeTrainingEfactor returns the new efactor using oldEfactor and MemQuality
+// TrainingEfactor is a median between oldEfactor and computeEfactor
+func computeTrainingEfactor(oldEfactor float32, quality models.MemQuality) float32 {
+	computedTrainingEfactor := (oldEfactor + computeEfactor(oldEfactor, quality)) / 2
+	if computedTrainingEfactor < 1.3 {
+		return 1.3
+	}
+	return computedTrainingEfactor
+}
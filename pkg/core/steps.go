@@ -0,0 +1,49 @@
+package core
+
+import (
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+)
+
+// ComputeLearningStep applies the deck learning steps to mem.
+// New cards go through Deck.GetLearnSteps and lapsed cards through Deck.GetRelearnSteps
+// before graduating into day based intervals.
+// It returns the delay before the next review and true if mem is in a learning step.
+func ComputeLearningStep(last, mem *models.Mem, validation bool, deck *models.Deck) (time.Duration, bool) {
+	inLearning := last.LearningStage <= models.StageToRelearn
+
+	steps, stage := deck.GetLearnSteps(), models.StageToLearn
+	if last.LearningStage == models.StageToRelearn || !inLearning {
+		steps, stage = deck.GetRelearnSteps(), models.StageToRelearn
+	}
+
+	if len(steps) == 0 {
+		return 0, false
+	}
+
+	if !validation {
+		mem.LearningStage = stage
+		mem.Step = 0
+		return steps[0], true
+	}
+
+	if !inLearning {
+		return 0, false
+	}
+
+	next := last.Step + 1
+	if int(next) >= len(steps) {
+		// The card graduates with the values computed by the scheduler
+		mem.Step = 0
+		return 0, false
+	}
+
+	// Learning steps don't move the card scheduling state
+	mem.Step = next
+	mem.LearningStage = stage
+	mem.Repetition, mem.Interval, mem.Efactor = last.Repetition, last.Interval, last.Efactor
+	mem.Stability, mem.Difficulty = last.Stability, last.Difficulty
+
+	return steps[next], true
+}
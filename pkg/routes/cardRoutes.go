@@ -11,23 +11,33 @@ func registerCardRoutes(r fiber.Router) {
 	r.Get("/cards/today", controllers.GetAllTodayCard)                   // Get all Today's card
 	r.Get("/cards/:deckID/training", controllers.GetTrainingCardsByDeck) // Get training card by deck
 
-	r.Get("/mcqs/:deckID", controllers.GetMcqsByDeck) // Get MCQs by deckID
+	r.Get("/mcqs/:deckID", controllers.GetMcqsByDeck)         // Get MCQs by deckID
+	r.Get("/cards/:id/answers", controllers.GetAnswersByCard) // Get the card alternative answers
 
 	// Post
 	r.Post("/cards/response", controllers.PostResponse)                 // Post a response
 	r.Post("/cards/selfresponse", controllers.PostSelfEvaluateResponse) // Post
+	r.Post("/cards/undo", controllers.UndoReview)                       // Undo the last review
+	r.Post("/cards/:id/suspend", controllers.SuspendCard)               // Suspend a card
+	r.Post("/cards/:id/unsuspend", controllers.UnsuspendCard)           // Unsuspend a card
+	r.Post("/cards/:id/bury", controllers.BuryCard)                     // Bury a card until tomorrow
+	r.Post("/cards/:id/reset", controllers.ResetCard)                   // Reset a card progress
+	r.Post("/cards/:id/override", controllers.OverrideResponse)         // Accept the last rejected response
 
 	// ADMIN ONLY
 	r.Get("/cards", controllers.GetAllCards)                   // Get all cards
 	r.Get("/cards/id/:id", controllers.GetCardByID)            // Get card by ID
 	r.Get("/cards/deck/:deckID", controllers.GetCardsFromDeck) // Get card by deckID
 
-	r.Post("/cards/new", controllers.CreateNewCard) // Create a new card
-	r.Post("/mcqs/new", controllers.CreateMcq)      // Create a mcq
+	r.Post("/cards/new", controllers.CreateNewCard)  // Create a new card
+	r.Post("/mcqs/new", controllers.CreateMcq)       // Create a mcq
+	r.Post("/answers/new", controllers.CreateAnswer) // Create an alternative answer
 
-	r.Put("/cards/:id/edit", controllers.UpdateCardByID) // Update a card by ID
-	r.Put("/mcqs/:id/edit", controllers.UpdateMcqByID)   // Update a mcq by ID
+	r.Put("/cards/:id/edit", controllers.UpdateCardByID)     // Update a card by ID
+	r.Put("/mcqs/:id/edit", controllers.UpdateMcqByID)       // Update a mcq by ID
+	r.Put("/answers/:id/edit", controllers.UpdateAnswerByID) // Update an alternative answer by ID
 
-	r.Delete("/cards/:id", controllers.DeleteCardByID) // Delete a card by ID
-	r.Delete("/mcqs/:id", controllers.DeleteMcqByID)   // Delete a mcq by ID
+	r.Delete("/cards/:id", controllers.DeleteCardByID)     // Delete a card by ID
+	r.Delete("/mcqs/:id", controllers.DeleteMcqByID)       // Delete a mcq by ID
+	r.Delete("/answers/:id", controllers.DeleteAnswerByID) // Delete an alternative answer by ID
 }
@@ -7,13 +7,16 @@ import (
 )
 
 func registerDeckRoutes(r fiber.Router) { // Get
-	r.Get("/decks", controllers.GetAllDecks)                    // Get all decks
-	r.Get("/decks/public", controllers.GetAllPublicDecks)       // Get all public decks
-	r.Get("/decks/available", controllers.GetAllAvailableDecks) // Get all available decks
-	r.Get("/decks/editor", controllers.GetAllEditorDecks)       // Get all decks the user is editor
-	r.Get("/decks/sub", controllers.GetAllSubDecks)             // Get all decks the user is sub to
-	r.Get("/decks/:deckID", controllers.GetDeckByID)            // Get deck by ID
-	r.Get("/decks/:deckID/users", controllers.GetAllSubUsers)   // Get all sub users
+	r.Get("/decks", controllers.GetAllDecks)                        // Get all decks
+	r.Get("/decks/public", controllers.GetAllPublicDecks)           // Get all public decks
+	r.Get("/decks/available", controllers.GetAllAvailableDecks)     // Get all available decks
+	r.Get("/decks/editor", controllers.GetAllEditorDecks)           // Get all decks the user is editor
+	r.Get("/decks/sub", controllers.GetAllSubDecks)                 // Get all decks the user is sub to
+	r.Get("/decks/:deckID", controllers.GetDeckByID)                // Get deck by ID
+	r.Get("/decks/:deckID/users", controllers.GetAllSubUsers)       // Get all sub users
+	r.Get("/decks/:deckID/stats", controllers.GetDeckStatistics)    // Get the user statistics on a deck
+	r.Get("/decks/:deckID/leeches", controllers.GetDeckLeeches)     // Get the deck leech report
+	r.Get("/decks/:deckID/overrides", controllers.GetDeckOverrides) // Get the deck override report
 
 	// Post
 	r.Post("/decks/new", controllers.CreateNewDeck)                             // Create a new deck
@@ -21,6 +24,7 @@ func registerDeckRoutes(r fiber.Router) { // Get
 	r.Post("/decks/:deckID/unsubscribe", controllers.UnSubToDeck)               // Unsubscribe to a deck
 	r.Post("/decks/private/:key/:code/subscribe", controllers.SubToPrivateDeck) // Subscribe to a private deck using key and code
 	r.Post("/decks/:deckID/publish", controllers.PublishDeckRequest)            // Request to publish a deck
+	r.Post("/decks/:deckID/reset", controllers.ResetDeck)                       // Reset the progress on a deck
 
 	// Put
 	r.Put("/decks/:deckID/edit", controllers.UpdateDeckByID) // Update a deck by ID
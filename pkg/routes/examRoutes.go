@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/memnix/memnixrest/app/controllers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func registerExamRoutes(r fiber.Router) {
+	// Get
+	r.Get("/exams/:id", controllers.GetExamByID)                          // Get an exam
+	r.Get("/decks/:deckID/exams", controllers.GetDeckExams)               // Get the user exams on a deck
+	r.Get("/decks/:deckID/exams/results", controllers.GetDeckExamResults) // Get the deck exam results
+
+	// Post
+	r.Post("/exams/new", controllers.CreateExam)        // Generate an exam
+	r.Post("/exams/:id/submit", controllers.SubmitExam) // Submit an exam
+}
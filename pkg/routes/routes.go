@@ -5,6 +5,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/memnix/memnixrest/app/controllers"
 	_ "github.com/memnix/memnixrest/docs" // Side effect import
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -34,7 +35,7 @@ func New() *fiber.App {
 
 	app.Use(cache.New(cache.Config{
 		Next: func(c *fiber.Ctx) bool {
-			return c.Query("refresh") == "true" || c.Path() == "/v1/user" || c.Path() == "/v1/login" || c.Path() == "/v1/register" || c.Path() == "/v1/logout"
+			return c.Query("refresh") == "true" || c.Path() == "/v1/user" || c.Path() == "/v1/login" || c.Path() == "/v1/register" || c.Path() == "/v1/logout" || c.Path() == "/v1/users/forecast" || c.Path() == "/v1/cards/today" || strings.HasSuffix(c.Path(), "/stats") || strings.HasSuffix(c.Path(), "/leeches") || strings.HasSuffix(c.Path(), "/answers") || strings.HasSuffix(c.Path(), "/overrides") || strings.HasPrefix(c.Path(), "/v1/sessions") || strings.Contains(c.Path(), "/exams")
 		},
 		Expiration:   2 * time.Minute,
 		CacheControl: true,
@@ -60,9 +61,11 @@ func New() *fiber.App {
 	})
 
 	// Register routes
-	registerUserRoutes(v1) // /v1/users/
-	registerDeckRoutes(v1) // /v1/decks/
-	registerCardRoutes(v1) // /v1/cards/
+	registerUserRoutes(v1)    // /v1/users/
+	registerDeckRoutes(v1)    // /v1/decks/
+	registerCardRoutes(v1)    // /v1/cards/
+	registerSessionRoutes(v1) // /v1/sessions/
+	registerExamRoutes(v1)    // /v1/exams/
 
 	return app
 }
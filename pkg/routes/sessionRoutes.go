@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/memnix/memnixrest/app/controllers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func registerSessionRoutes(r fiber.Router) {
+	// Get
+	r.Get("/sessions/current", controllers.GetCurrentSession)     // Get the active session to resume it
+	r.Get("/sessions/:id/summary", controllers.GetSessionSummary) // Get a session summary
+
+	// Post
+	r.Post("/sessions/new", controllers.CreateSession)  // Start a session
+	r.Post("/sessions/:id/end", controllers.EndSession) // Finish a session
+}
@@ -8,11 +8,17 @@ import (
 
 func registerUserRoutes(r fiber.Router) {
 	// Get
-	r.Get("/users", controllers.GetAllUsers)        // Get all users
-	r.Get("/users/id/:id", controllers.GetUserByID) // Get user by ID
+	r.Get("/users", controllers.GetAllUsers)          // Get all users
+	r.Get("/users/id/:id", controllers.GetUserByID)   // Get user by ID
+	r.Get("/users/forecast", controllers.GetForecast) // Get the review forecast
+	r.Get("/users/stats", controllers.GetStatistics)  // Get the user statistics
 
 	// Post
 	r.Post("/users/settings/:deckID/today", controllers.SetTodayConfig)
+	r.Post("/users/settings/:deckID/limits", controllers.SetLimitsConfig)
+	r.Post("/users/settings/:deckID/exam", controllers.SetExamDateConfig)
+	r.Post("/users/settings/time", controllers.SetTimeConfig)
+	r.Post("/users/settings/balance", controllers.SetBalanceConfig)
 	r.Post("/users/resetpassword", controllers.ResetPassword)
 	r.Post("/users/confirmpassword", controllers.ResetPasswordConfirm)
 
@@ -1,5 +1,7 @@
 package utils
 
+import "time"
+
 const MaxDeckNormalUser = 5
 const MaxCardDeck = 200
 const MaxMcqDeck = 100
@@ -7,6 +9,8 @@ const MaxMcqDeck = 100
 const MaxPasswordLen = 50
 const MaxEmailLen = 100
 const MaxUsernameLen = 15
+const MaxTimezoneLen = 50
+const MaxDayStartHour = 23
 
 const MaxDefaultLen = 200
 
@@ -15,11 +19,43 @@ const MaxCardFormatLen = 50
 const MaxImageURLLen = 200
 const MaxCardExplicationLen = 500
 
+const MaxDailyLimit = 9999
+const MaxForecastDays = 365
+const DefaultForecastDays = 7
+const MaxStatisticsDays = 365
+const DefaultStatisticsDays = 30
+const MaxUndoReviews = 5
+const MaxLeechThreshold = 99
+const MaxLeechReport = 20
+const MaxOverrideReport = 50
+const ExpressionSamples = 16
+const MaxExpressionDepth = 64
+const FieldSeparator = "/"
+const SessionRequeueGap = 3
+const MaxSessionIdle = 5 * time.Minute
+const MaxExamQuestions = 100
+const MaxExamTimeLimit = 3 * 60 * 60
+const ExamTimeGrace = 30 * time.Second
+const MaxExamResults = 100
+
 const MaxDeckNameLen = 42
 const MinDeckNameLen = 5
 const DeckKeyLen = 4
 const MaxLangLen = 2
+const MaxStepsLen = 50
+const MaxSteps = 10
+const MaxStepDuration = 24 * time.Hour
 
-const MaxMcqAnswersLen = 150
-const MinMcqAnswersLen = 4
+const MaxMcqPool = 150
+const MinMcqOptions = 2
+const MaxMcqOptions = 8
+const DefaultMcqOptions = 4
 const MaxMcqName = 50
+const MaxMcqRepetitions = 20
+const MaxMcqEfactor = 2.5
+const DefaultMcqEfactor = 2.3
+const DefaultMcqHardEfactor = 1.7
+const ExperiencedRepetitions = 3
+const MinDistractorSimilarity = 0.5
+const DistractorConfusionWeight = 2.0
+const DistractorJitter = 0.3
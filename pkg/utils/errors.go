@@ -8,3 +8,15 @@ const ErrorDeckName = "A deck name should be at least 5 char long."
 const ErrorNotSub = "You are not sub to this deck !"
 const ErrorAlreadyUsedEmail = "There is already an account using this email."
 const ErrorAlreadySub = "You are already sub to this deck."
+const ErrorTimeConfig = "You must provide a valid timezone and a day start hour between 0 and 23."
+const ErrorDailyLimits = "Daily limits must be between 0 and 9999."
+const ErrorForecastDays = "The forecast must be between 1 and 365 days."
+const ErrorStatisticsDays = "The statistics must be between 1 and 365 days."
+const ErrorNoUndo = "There is no recent review to undo."
+const ErrorNoOverride = "There is no rejected response to override on this card."
+const ErrorAnswerLen = "An answer must not be empty and must be at most 200 char long."
+const ErrorNoSession = "There is no active study session."
+const ErrorExamConfig = "An exam must have between 1 and 100 questions, a mcq percentage up to 100 and a time limit up to 3 hours."
+const ErrorExamSubmitted = "This exam has already been submitted."
+const ErrorExamDate = "The exam date can't be in the past."
+const ErrorResponseLen = "A response and each of its fields must be at most 200 char long."
@@ -0,0 +1,323 @@
+package utils
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is a parsed math expression
+type Expression struct {
+	root      exprNode
+	Variables map[string]bool
+}
+
+// exprNode is a node of an expression tree
+type exprNode interface {
+	eval(vars map[string]float64) float64
+}
+
+type exprNumber float64
+
+type exprVariable string
+
+type exprUnary struct {
+	op      rune
+	operand exprNode
+}
+
+type exprBinary struct {
+	op          rune
+	left, right exprNode
+}
+
+type exprFunction struct {
+	fn       func(float64) float64
+	argument exprNode
+}
+
+func (n exprNumber) eval(map[string]float64) float64 { return float64(n) }
+
+func (n exprVariable) eval(vars map[string]float64) float64 { return vars[string(n)] }
+
+func (n exprUnary) eval(vars map[string]float64) float64 { return -n.operand.eval(vars) }
+
+func (n exprFunction) eval(vars map[string]float64) float64 { return n.fn(n.argument.eval(vars)) }
+
+func (n exprBinary) eval(vars map[string]float64) float64 {
+	left, right := n.left.eval(vars), n.right.eval(vars)
+
+	switch n.op {
+	case '+':
+		return left + right
+	case '-':
+		return left - right
+	case '*':
+		return left * right
+	case '/':
+		return left / right
+	default:
+		return math.Pow(left, right)
+	}
+}
+
+var exprFunctions = map[string]func(float64) float64{
+	"sqrt": math.Sqrt, "sin": math.Sin, "cos": math.Cos, "tan": math.Tan,
+	"ln": math.Log, "log": math.Log10, "exp": math.Exp, "abs": math.Abs,
+}
+
+var exprConstants = map[string]float64{"pi": math.Pi, "π": math.Pi, "e": math.E}
+
+// exprNames are the function and constant names, longest first
+var exprNames = []string{"sqrt", "sin", "cos", "tan", "exp", "abs", "ln", "log", "pi", "π", "e"}
+
+type exprToken struct {
+	kind  rune // 'n': number, 'v': variable, 'f': function, 'c': constant, otherwise the operator
+	text  string
+	value float64
+}
+
+// ParseExpression parses a math expression
+// Single letters are variables and implicit multiplications such as 2x or 2(x+1) are supported
+func ParseExpression(s string) (*Expression, bool) {
+	tokens, ok := tokenizeExpression(s)
+	if !ok || len(tokens) == 0 {
+		return nil, false
+	}
+
+	parser := &exprParser{tokens: tokens, variables: make(map[string]bool)}
+
+	root, ok := parser.parseExpr()
+	if !ok || parser.position != len(tokens) {
+		return nil, false
+	}
+
+	return &Expression{root: root, Variables: parser.variables}, true
+}
+
+// Eval evaluates the expression with the given variable values
+func (e *Expression) Eval(vars map[string]float64) float64 {
+	return e.root.eval(vars)
+}
+
+// EquivalentExpressions returns if two expressions are equal at random sample points
+func EquivalentExpressions(a, b *Expression) bool {
+	variables := make([]string, 0, len(a.Variables)+len(b.Variables))
+	for name := range a.Variables {
+		variables = append(variables, name)
+	}
+	for name := range b.Variables {
+		if !a.Variables[name] {
+			variables = append(variables, name)
+		}
+	}
+
+	random := rand.New(rand.NewSource(1)) // Sample points are the same for every check
+	valid := 0
+
+	for i := 0; i < ExpressionSamples; i++ {
+		vars := make(map[string]float64, len(variables))
+		for _, name := range variables {
+			vars[name] = random.Float64()*6 - 3
+		}
+
+		left, right := a.Eval(vars), b.Eval(vars)
+		if math.IsNaN(left) || math.IsNaN(right) || math.IsInf(left, 0) || math.IsInf(right, 0) {
+			continue
+		}
+
+		if math.Abs(left-right) > 1e-7*math.Max(1, math.Max(math.Abs(left), math.Abs(right))) {
+			return false
+		}
+		valid++
+	}
+
+	return valid*2 >= ExpressionSamples
+}
+
+func tokenizeExpression(s string) ([]exprToken, bool) {
+	s = strings.NewReplacer("**", "^", "×", "*", "·", "*", "÷", "/", "−", "-", ",", ".").Replace(s)
+	runes := []rune(s)
+
+	var tokens []exprToken
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			value, err := strconv.ParseFloat(string(runes[start:i]), 64)
+			if err != nil {
+				return nil, false
+			}
+			tokens = append(tokens, exprToken{kind: 'n', value: value})
+		case unicode.IsLetter(r):
+			token := exprToken{kind: 'v', text: string(r)}
+			for _, name := range exprNames {
+				if strings.HasPrefix(string(runes[i:]), name) {
+					token.text = name
+					token.kind = 'c'
+					if _, ok := exprFunctions[name]; ok {
+						token.kind = 'f'
+					}
+					break
+				}
+			}
+			i += len([]rune(token.text))
+			tokens = append(tokens, token)
+		case strings.ContainsRune("+-*/^()", r):
+			tokens = append(tokens, exprToken{kind: r})
+			i++
+		default:
+			return nil, false
+		}
+	}
+
+	return tokens, true
+}
+
+type exprParser struct {
+	tokens    []exprToken
+	position  int
+	depth     int // Nesting of parentheses, signs and functions, bounded by MaxExpressionDepth
+	variables map[string]bool
+}
+
+// enter counts a nesting level and returns false past MaxExpressionDepth
+func (p *exprParser) enter() bool {
+	p.depth++
+	return p.depth <= MaxExpressionDepth
+}
+
+func (p *exprParser) leave() {
+	p.depth--
+}
+
+func (p *exprParser) peek() rune {
+	if p.position >= len(p.tokens) {
+		return 0
+	}
+	return p.tokens[p.position].kind
+}
+
+// parseExpr parses additions and subtractions
+func (p *exprParser) parseExpr() (exprNode, bool) {
+	left, ok := p.parseTerm()
+	for ok && (p.peek() == '+' || p.peek() == '-') {
+		op := p.peek()
+		p.position++
+
+		var right exprNode
+		if right, ok = p.parseTerm(); ok {
+			left = exprBinary{op: op, left: left, right: right}
+		}
+	}
+	return left, ok
+}
+
+// parseTerm parses multiplications, divisions and implicit multiplications
+func (p *exprParser) parseTerm() (exprNode, bool) {
+	left, ok := p.parseUnary()
+	for ok {
+		op := p.peek()
+		switch op {
+		case '*', '/':
+			p.position++
+		case 'n', 'v', 'f', 'c', '(':
+			op = '*'
+		default:
+			return left, ok
+		}
+
+		var right exprNode
+		if right, ok = p.parseUnary(); ok {
+			left = exprBinary{op: op, left: left, right: right}
+		}
+	}
+	return left, ok
+}
+
+// parseUnary parses signs
+func (p *exprParser) parseUnary() (exprNode, bool) {
+	switch p.peek() {
+	case '-', '+':
+		if !p.enter() {
+			return nil, false
+		}
+		defer p.leave()
+
+		op := p.peek()
+		p.position++
+		operand, ok := p.parseUnary()
+		if op == '+' {
+			return operand, ok
+		}
+		return exprUnary{op: '-', operand: operand}, ok
+	default:
+		return p.parsePower()
+	}
+}
+
+// parsePower parses right associative powers
+func (p *exprParser) parsePower() (exprNode, bool) {
+	base, ok := p.parsePrimary()
+	if !ok || p.peek() != '^' {
+		return base, ok
+	}
+	p.position++
+
+	exponent, ok := p.parseUnary()
+	return exprBinary{op: '^', left: base, right: exponent}, ok
+}
+
+// parsePrimary parses numbers, variables, constants, functions and parentheses
+func (p *exprParser) parsePrimary() (exprNode, bool) {
+	if p.position >= len(p.tokens) {
+		return nil, false
+	}
+
+	token := p.tokens[p.position]
+	p.position++
+
+	if token.kind == 'f' || token.kind == '(' {
+		if !p.enter() {
+			return nil, false
+		}
+		defer p.leave()
+	}
+
+	switch token.kind {
+	case 'n':
+		return exprNumber(token.value), true
+	case 'v':
+		p.variables[token.text] = true
+		return exprVariable(token.text), true
+	case 'c':
+		return exprNumber(exprConstants[token.text]), true
+	case 'f':
+		// sin(x)^2 is the power of sin(x), sin x^2 the sine of x^2
+		parse := p.parsePower
+		if p.peek() == '(' {
+			parse = p.parsePrimary
+		}
+		argument, ok := parse()
+		return exprFunction{fn: exprFunctions[token.text], argument: argument}, ok
+	case '(':
+		inner, ok := p.parseExpr()
+		if !ok || p.peek() != ')' {
+			return nil, false
+		}
+		p.position++
+		return inner, true
+	default:
+		return nil, false
+	}
+}
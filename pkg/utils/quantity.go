@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Quantity is a number with an optional unit
+type Quantity struct {
+	Value float64
+	Unit  string
+}
+
+// unit is a unit of measure and its factor to the base unit of its dimension
+type unit struct {
+	Dimension string
+	Factor    float64
+}
+
+var units = map[string]unit{
+	"mm": {"length", 0.001}, "cm": {"length", 0.01}, "dm": {"length", 0.1}, "m": {"length", 1}, "km": {"length", 1000},
+	"in": {"length", 0.0254}, "ft": {"length", 0.3048}, "yd": {"length", 0.9144}, "mi": {"length", 1609.344},
+	"mg": {"mass", 0.001}, "g": {"mass", 1}, "kg": {"mass", 1000}, "t": {"mass", 1000000},
+	"oz": {"mass", 28.349523125}, "lb": {"mass", 453.59237},
+	"ms": {"time", 0.001}, "s": {"time", 1}, "min": {"time", 60}, "h": {"time", 3600}, "d": {"time", 86400},
+	"ml": {"volume", 0.001}, "cl": {"volume", 0.01}, "dl": {"volume", 0.1}, "l": {"volume", 1},
+	"m2": {"area", 1}, "m²": {"area", 1}, "km2": {"area", 1000000}, "km²": {"area", 1000000}, "ha": {"area", 10000},
+	"km/h": {"speed", 1 / 3.6}, "m/s": {"speed", 1}, "mph": {"speed", 0.44704},
+}
+
+// dotDecimalLangs are the Deck.Lang using a dot as decimal separator
+// Other languages use a comma
+var dotDecimalLangs = map[string]bool{"": true, "en": true, "ja": true, "zh": true, "ko": true, "he": true, "hi": true, "th": true}
+
+// ParseQuantity parses a number followed by an optional unit using the decimal separator of lang
+// Digit group separators are ignored
+func ParseQuantity(s, lang string) (Quantity, bool) {
+	s = strings.TrimSpace(s)
+
+	end := 0
+	for i, r := range s {
+		if !unicode.IsDigit(r) && !strings.ContainsRune("+-.,' \u00a0\u202f", r) {
+			break
+		}
+		end = i + len(string(r))
+	}
+
+	value, ok := parseNumber(s[:end], lang)
+	if !ok {
+		return Quantity{}, false
+	}
+
+	return Quantity{Value: value, Unit: strings.TrimSpace(s[end:])}, true
+}
+
+// Convert returns the quantity value in another unit of the same dimension
+func (q Quantity) Convert(to string) (float64, bool) {
+	if q.Unit == to {
+		return q.Value, true
+	}
+
+	from, ok := findUnit(q.Unit)
+	if !ok {
+		return 0, false
+	}
+	target, ok := findUnit(to)
+	if !ok || from.Dimension != target.Dimension {
+		return 0, false
+	}
+
+	return q.Value * from.Factor / target.Factor, true
+}
+
+func findUnit(name string) (unit, bool) {
+	if u, ok := units[name]; ok {
+		return u, true
+	}
+	u, ok := units[strings.ToLower(name)]
+	return u, ok
+}
+
+// parseNumber parses a number written with the separators of lang
+func parseNumber(s, lang string) (float64, bool) {
+	s = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "", "'", "").Replace(s)
+	if s == "" {
+		return 0, false
+	}
+
+	decimal, group := ",", "."
+	if dotDecimalLangs[lang] {
+		decimal, group = ".", ","
+	}
+
+	switch {
+	case strings.Contains(s, decimal) && strings.Contains(s, group):
+		// Both separators are used, the last one is the decimal separator
+		if strings.LastIndex(s, group) > strings.LastIndex(s, decimal) {
+			decimal, group = group, decimal
+		}
+	case strings.Count(s, group) == 1 && len(s)-strings.Index(s, group)-1 != 3:
+		// A single separator which can't be a digit group is a decimal separator
+		decimal, group = group, decimal
+	}
+
+	s = strings.ReplaceAll(s, group, "")
+	s = strings.Replace(s, decimal, ".", 1)
+
+	value, err := strconv.ParseFloat(s, 64)
+	return value, err == nil
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/core"
+)
+
+func TestValidateAnswer(t *testing.T) {
+	answers := []models.Answer{{Answer: "Forty two"}, {Answer: "XLII"}}
+
+	tests := []struct {
+		name     string
+		response string
+		card     models.Card
+		answers  []models.Answer
+		want     string
+		wantType core.AnswerMatch
+	}{
+		{"card answer", "42", models.Card{Answer: "42"}, answers, "42", core.MatchExact},
+		{"alternative answer", "forty two", models.Card{Answer: "42"}, answers, "Forty two", core.MatchExact},
+		{"alternative answer without spaces", "fortytwo", models.Card{Answer: "42"}, answers, "Forty two", core.MatchExact},
+		{"alternative answer with spaces", "fortytwo", models.Card{Answer: "42", Spaces: true}, answers, "", core.MatchNone},
+		{"alternative answer with case", "xlii", models.Card{Answer: "42", Case: true}, answers, "", core.MatchNone},
+		{"wrong answer", "41", models.Card{Answer: "42"}, answers, "", core.MatchNone},
+		{"typo not allowed", "Tchaikovski", models.Card{Answer: "Tchaikovsky"}, nil, "", core.MatchNone},
+		{"typo allowed", "Tchaikovski", models.Card{Answer: "Tchaikovsky", AllowTypos: true}, nil, "Tchaikovsky", core.MatchAlmost},
+		{"too many typos", "Tchekhov", models.Card{Answer: "Tchaikovsky", AllowTypos: true}, nil, "", core.MatchNone},
+		{"no typo on short answers", "43", models.Card{Answer: "42", AllowTypos: true}, nil, "", core.MatchNone},
+		{"accents", "Etre", models.Card{Answer: "Être"}, nil, "", core.MatchNone},
+		{"ignore accents", "Etre", models.Card{Answer: "Être", IgnoreAccents: true}, nil, "Être", core.MatchExact},
+		{"ignore punctuation", "Hello world", models.Card{Answer: "Hello, world!", IgnorePunctuation: true}, nil, "Hello, world!", core.MatchExact},
+		{"ignore articles", "chat", models.Card{Answer: "Le chat", IgnoreArticles: true, Deck: models.Deck{Lang: "fr"}}, nil, "Le chat", core.MatchExact},
+		{"ignore elided articles", "arbre", models.Card{Answer: "L'arbre", IgnoreArticles: true, Deck: models.Deck{Lang: "fr"}}, nil, "L'arbre", core.MatchExact},
+		{"articles of another lang", "chat", models.Card{Answer: "The chat", IgnoreArticles: true, Deck: models.Deck{Lang: "fr"}}, nil, "", core.MatchNone},
+		{"number", "42.0", models.Card{Answer: "42", Type: models.CardInt}, nil, "42", core.MatchExact},
+		{"number with comma decimal", "3,14", models.Card{Answer: "3.14", Type: models.CardInt, Deck: models.Deck{Lang: "fr"}}, nil, "3.14", core.MatchExact},
+		{"number with digit groups", "1,500", models.Card{Answer: "1500", Type: models.CardInt, Deck: models.Deck{Lang: "en"}}, nil, "1500", core.MatchExact},
+		{"number out of tolerance", "3.2", models.Card{Answer: "3.14", Type: models.CardInt, Tolerance: 0.05}, nil, "", core.MatchNone},
+		{"number in tolerance", "3.1", models.Card{Answer: "3.14", Type: models.CardInt, Tolerance: 0.05}, nil, "3.14", core.MatchExact},
+		{"number in relative tolerance", "105", models.Card{Answer: "100", Type: models.CardInt, Tolerance: 0.1, RelativeTolerance: true}, nil, "100", core.MatchExact},
+		{"number without unit", "1.5", models.Card{Answer: "1.5 km", Type: models.CardInt}, nil, "1.5 km", core.MatchExact},
+		{"number with another unit", "1500 m", models.Card{Answer: "1.5 km", Type: models.CardInt}, nil, "", core.MatchNone},
+		{"number with accepted units", "1500 m", models.Card{Answer: "1.5 km", Type: models.CardInt, AcceptUnits: true}, nil, "1.5 km", core.MatchExact},
+		{"expression", "2x+2", models.Card{Answer: "2(x+1)", Type: models.CardExpression}, nil, "2(x+1)", core.MatchExact},
+		{"expression with powers", "x^2 + 2*x*y + y^2", models.Card{Answer: "(x+y)^2", Type: models.CardExpression}, nil, "(x+y)^2", core.MatchExact},
+		{"expression with functions", "2 sin(x) cos(x)", models.Card{Answer: "sin(2x)", Type: models.CardExpression}, nil, "sin(2x)", core.MatchExact},
+		{"power of a function", "sin(x)*sin(x)", models.Card{Answer: "sin(x)^2", Type: models.CardExpression}, nil, "sin(x)^2", core.MatchExact},
+		{"function of a power", "sin(x^2)", models.Card{Answer: "sin(x)^2", Type: models.CardExpression}, nil, "", core.MatchNone},
+		{"expression with unary minus", "-x^2", models.Card{Answer: "-(x^2)", Type: models.CardExpression}, nil, "-(x^2)", core.MatchExact},
+		{"wrong expression", "2x+1", models.Card{Answer: "2(x+1)", Type: models.CardExpression}, nil, "", core.MatchNone},
+		{"invalid expression", "2x+", models.Card{Answer: "2(x+1)", Type: models.CardExpression}, nil, "", core.MatchNone},
+		{"nested expression", strings.Repeat("(", 20) + "2x+2" + strings.Repeat(")", 20), models.Card{Answer: "2(x+1)", Type: models.CardExpression}, nil, "2(x+1)", core.MatchExact},
+		{"too deeply nested expression", strings.Repeat("(", 100000) + "x", models.Card{Answer: "x", Type: models.CardExpression}, nil, "", core.MatchNone},
+		{"too many signs", strings.Repeat("-", 100000) + "x", models.Card{Answer: "x", Type: models.CardExpression}, nil, "", core.MatchNone},
+		{"number with another dimension", "1500 g", models.Card{Answer: "1.5 km", Type: models.CardInt, AcceptUnits: true}, nil, "", core.MatchNone},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, match := core.ValidateAnswer(test.response, &test.card, test.answers)
+			if got != test.want || match != test.wantType {
+				t.Errorf("ValidateAnswer() = %q, %d, want %q, %d", got, match, test.want, test.wantType)
+			}
+		})
+	}
+}
+
+func TestValidateResponseMCQ(t *testing.T) {
+	card := models.Card{Answer: "Tchaikovsky", AllowTypos: true, IgnoreArticles: true, Deck: models.Deck{Lang: "en"}}
+
+	tests := []struct {
+		name       string
+		response   string
+		mcq        bool
+		want       bool
+		wantAlmost bool
+	}{
+		{"correct option", "Tchaikovsky", true, true, false},
+		{"look-alike option", "Tchaikovski", true, false, false},
+		{"typed look-alike", "Tchaikovski", false, true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validation := core.ValidateResponse(&models.CardResponse{Response: test.response}, &card, nil, test.mcq)
+			if validation.Validate != test.want || validation.Almost != test.wantAlmost {
+				t.Errorf("ValidateResponse() = %t, %t, want %t, %t", validation.Validate, validation.Almost, test.want, test.wantAlmost)
+			}
+		})
+	}
+}
+
+func TestCardResponseValidation(t *testing.T) {
+	long := strings.Repeat("x", 201)
+
+	tests := []struct {
+		name     string
+		response models.CardResponse
+		want     bool
+	}{
+		{"short response", models.CardResponse{Response: "42"}, false},
+		{"too long response", models.CardResponse{Response: long}, true},
+		{"too long field", models.CardResponse{Fields: []string{"1789", long}}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.response.NotValidate(); got != test.want {
+				t.Errorf("NotValidate() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
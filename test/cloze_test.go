@@ -0,0 +1,53 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/memnix/memnixrest/app/models"
+)
+
+func TestCloze(t *testing.T) {
+	card := models.Card{
+		Type:     models.CardCloze,
+		Question: "{{c2::Paris}} is the capital of {{c1::France::country}}, {{c2::Berlin}} of Germany",
+	}
+
+	if got, want := card.GetClozes(), []uint{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetClozes() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name         string
+		cloze        uint
+		wantQuestion string
+		wantAnswer   string
+	}{
+		{"cloze with hint", 1, "Paris is the capital of [country], Berlin of Germany", "France"},
+		{"cloze with several spans", 2, "[...] is the capital of France, [...] of Germany", "Paris, Berlin"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := card.GetClozeQuestion(test.cloze); got != test.wantQuestion {
+				t.Errorf("GetClozeQuestion() = %q, want %q", got, test.wantQuestion)
+			}
+			if got := card.GetClozeAnswer(test.cloze); got != test.wantAnswer {
+				t.Errorf("GetClozeAnswer() = %q, want %q", got, test.wantAnswer)
+			}
+		})
+	}
+
+	if card.NotValidate() {
+		t.Errorf("NotValidate() = true, want false")
+	}
+
+	card.McqID.Int32, card.McqID.Valid = 1, true
+	if !card.NotValidate() {
+		t.Errorf("NotValidate() with an mcq = false, want true")
+	}
+
+	if got := (&models.Card{Type: models.CardString}).GetClozes(); !reflect.DeepEqual(got, []uint{0}) {
+		t.Errorf("GetClozes() on a string card = %v, want [0]", got)
+	}
+}
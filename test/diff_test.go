@@ -0,0 +1,53 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/core"
+)
+
+func TestDiffAnswer(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		card       models.Card
+		answers    []models.Answer
+		wantOps    []models.DiffOp
+		wantErrors []models.AnswerError
+	}{
+		{"missing word", "Pyotr Tchaikovsky", models.Card{Answer: "Pyotr Ilyich Tchaikovsky"}, nil,
+			[]models.DiffOp{models.DiffEqual, models.DiffMissing, models.DiffEqual}, []models.AnswerError{models.AnswerErrorMissingWord}},
+		{"extra word", "the Eiffel Tower", models.Card{Answer: "Eiffel Tower"}, nil,
+			[]models.DiffOp{models.DiffExtra, models.DiffEqual, models.DiffEqual}, []models.AnswerError{models.AnswerErrorExtraWord}},
+		{"word order", "Tower Eiffel", models.Card{Answer: "Eiffel Tower"}, nil,
+			[]models.DiffOp{models.DiffExtra, models.DiffEqual, models.DiffMissing}, []models.AnswerError{models.AnswerErrorTransposition}},
+		{"swapped letters", "Tchaikovksy", models.Card{Answer: "Tchaikovsky"}, nil,
+			[]models.DiffOp{models.DiffReplace}, []models.AnswerError{models.AnswerErrorTransposition}},
+		{"accent", "Ecole normale", models.Card{Answer: "École normale"}, nil,
+			[]models.DiffOp{models.DiffReplace, models.DiffEqual}, []models.AnswerError{models.AnswerErrorAccent}},
+		{"typo", "Tchaikovski", models.Card{Answer: "Tchaikovsky"}, nil,
+			[]models.DiffOp{models.DiffReplace}, []models.AnswerError{models.AnswerErrorTypo}},
+		{"closest accepted answer", "Peter Tchaikovsky", models.Card{Answer: "Pyotr Ilyich Tchaikovsky"}, []models.Answer{{Answer: "Peter Ilyich Tchaikovsky"}},
+			[]models.DiffOp{models.DiffEqual, models.DiffMissing, models.DiffEqual}, []models.AnswerError{models.AnswerErrorMissingWord}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parts, answerErrors := core.DiffAnswer(test.response, &test.card, test.answers)
+
+			ops := make([]models.DiffOp, len(parts))
+			for i := range parts {
+				ops[i] = parts[i].Op
+			}
+
+			if !reflect.DeepEqual(ops, test.wantOps) {
+				t.Errorf("DiffAnswer() ops = %v, want %v", ops, test.wantOps)
+			}
+			if !reflect.DeepEqual(answerErrors, test.wantErrors) {
+				t.Errorf("DiffAnswer() errors = %v, want %v", answerErrors, test.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/core"
+	"github.com/memnix/memnixrest/pkg/utils"
+)
+
+func TestDistractorSimilarity(t *testing.T) {
+	tests := []struct {
+		name    string
+		answer  string
+		option  string
+		similar bool
+	}{
+		{"same shape", "Louis XIV", "Henri IV", true},
+		{"same length words", "Paris", "Rome", true},
+		{"years", "1789", "1815", true},
+		{"year and city", "1789", "Paris", false},
+		{"word and sentence", "Paris", "the city of lights on the Seine", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := core.DistractorSimilarity(test.answer, test.option)
+			if (got >= utils.MinDistractorSimilarity) != test.similar {
+				t.Errorf("DistractorSimilarity(%q, %q) = %.2f, similar want %t", test.answer, test.option, got, test.similar)
+			}
+		})
+	}
+}
+
+func TestSelectDistractors(t *testing.T) {
+	pool := []models.McqOption{
+		{Answer: "Paris", Image: "paris.png"},
+		{Answer: "Rome"},
+		{Answer: "Berlin"},
+		{Answer: "Rome"},
+		{Answer: "1789"},
+		{Answer: "Madrid; Spain"},
+	}
+
+	tests := []struct {
+		name        string
+		answer      string
+		count       int
+		confusions  map[string]int
+		experienced bool
+		wantLen     int
+		wantOption  string
+	}{
+		{"two options", "Paris", 2, nil, false, 2, ""},
+		{"whole pool", "Paris", 5, nil, false, 5, "1789"},
+		{"answer outside the pool", "Lisbon", 6, nil, false, 6, ""},
+		{"confusion comes first", "Paris", 2, map[string]int{"1789": 1}, false, 2, "1789"},
+		{"experienced without easy options", "Paris", 5, nil, true, 0, ""},
+		{"experienced keeps confusions", "Paris", 4, map[string]int{"1789": 2}, true, 4, "1789"},
+		{"not enough distinct options", "Paris", 6, nil, false, 0, ""},
+		{"invalid count", "Paris", 1, nil, false, 0, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := core.SelectDistractors(append([]models.McqOption(nil), pool...), test.answer, test.count, test.confusions, test.experienced)
+			if len(options) != test.wantLen {
+				t.Fatalf("len(SelectDistractors()) = %d, want %d", len(options), test.wantLen)
+			}
+			if test.wantLen == 0 {
+				return
+			}
+
+			seen := make(map[string]bool)
+			for _, option := range options {
+				if seen[option.Answer] {
+					t.Errorf("option %q is duplicated", option.Answer)
+				}
+				seen[option.Answer] = true
+				if option.Answer == "Paris" && option.Image != "paris.png" {
+					t.Errorf("answer image = %q, want %q", option.Image, "paris.png")
+				}
+			}
+			if !seen[test.answer] {
+				t.Errorf("answer %q is missing from %v", test.answer, options)
+			}
+			if test.wantOption != "" && !seen[test.wantOption] {
+				t.Errorf("option %q is missing from %v", test.wantOption, options)
+			}
+		})
+	}
+}
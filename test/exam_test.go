@@ -0,0 +1,120 @@
+package test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/utils"
+)
+
+func TestExamGrade(t *testing.T) {
+	start := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		timeLimit   uint
+		elapsed     time.Duration
+		scores      []float64 // Question scores, 1 is a validated answer
+		wantCorrect uint
+		wantScore   float64
+		wantLate    bool
+	}{
+		{"every answer correct", 600, 5 * time.Minute, []float64{1, 1}, 2, 1, false},
+		{"partial answers count", 600, 5 * time.Minute, []float64{1, 0.5, 0, 0.5}, 1, 0.5, false},
+		{"no question", 0, time.Minute, nil, 0, 0, false},
+		{"within the grace delay", 600, 10*time.Minute + utils.ExamTimeGrace/2, []float64{1}, 1, 1, false},
+		{"late submission scores 0", 600, 10*time.Minute + 2*utils.ExamTimeGrace, []float64{1, 0.5}, 0, 0, true},
+		{"no time limit", 0, 24 * time.Hour, []float64{0}, 0, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exam := &models.Exam{TimeLimit: test.timeLimit, Status: models.ExamStarted}
+			exam.CreatedAt = start
+			for _, score := range test.scores {
+				exam.Questions = append(exam.Questions, models.ExamQuestion{Validate: score == 1, Score: score})
+			}
+
+			exam.Grade(start.Add(test.elapsed))
+
+			if exam.Status != models.ExamSubmitted {
+				t.Errorf("status = %s, want %s", exam.Status.ToString(), models.ExamSubmitted.ToString())
+			}
+			if exam.Total != uint(len(test.scores)) || exam.Correct != test.wantCorrect {
+				t.Errorf("correct = %d/%d, want %d/%d", exam.Correct, exam.Total, test.wantCorrect, len(test.scores))
+			}
+			if math.Abs(exam.Score-test.wantScore) > 1e-9 {
+				t.Errorf("score = %f, want %f", exam.Score, test.wantScore)
+			}
+			if exam.Late != test.wantLate {
+				t.Errorf("late = %v, want %v", exam.Late, test.wantLate)
+			}
+			if exam.Duration != uint(test.elapsed.Seconds()) {
+				t.Errorf("duration = %d, want %d", exam.Duration, uint(test.elapsed.Seconds()))
+			}
+		})
+	}
+}
+
+func TestExamQuestionValidation(t *testing.T) {
+	correct, almost, partial, incorrect := new(models.CardResponseValidation), new(models.CardResponseValidation), new(models.CardResponseValidation), new(models.CardResponseValidation)
+	correct.SetCorrect()
+	almost.SetAlmost()
+	partial.SetPartial(0.5)
+	incorrect.SetIncorrect()
+
+	tests := []struct {
+		name       string
+		validation *models.CardResponseValidation
+		wantScore  float64
+	}{
+		{"correct", correct, 1},
+		{"almost", almost, 1},
+		{"partial", partial, 0.5},
+		{"incorrect", incorrect, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			question := new(models.ExamQuestion)
+			question.SetValidation("42", test.validation)
+
+			if question.Response != "42" || question.Validate != test.validation.Validate || question.Almost != test.validation.Almost {
+				t.Errorf("question = %+v, want the validation %+v", question, test.validation)
+			}
+			if question.Score != test.wantScore {
+				t.Errorf("score = %f, want %f", question.Score, test.wantScore)
+			}
+		})
+	}
+}
+
+func TestExamQuestionSetOptions(t *testing.T) {
+	options := []models.McqOption{{McqID: 1, Answer: "1789", Image: "a.png"}, {Answer: "1792"}}
+	options[0].ID = 3
+
+	question := new(models.ExamQuestion)
+	question.SetOptions(options)
+
+	if question.Type != models.CardMCQ || len(question.Options) != 2 {
+		t.Fatalf("SetOptions() = %d options of type %d, want 2 of type %d", len(question.Options), question.Type, models.CardMCQ)
+	}
+
+	data, err := json.Marshal(question.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{"ID", "mcq_id", "CreatedAt"} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("options %s expose %s", data, field)
+		}
+	}
+	if question.Options[0].Image != "a.png" {
+		t.Errorf("option image = %q, want %q", question.Options[0].Image, "a.png")
+	}
+}
@@ -0,0 +1,61 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/core"
+)
+
+func TestValidateFields(t *testing.T) {
+	card := models.Card{
+		Type:       models.CardString,
+		Format:     "Date / Name / Country",
+		Answer:     "1789 / Bastille / France",
+		FieldTypes: "1",
+		AllowTypos: true,
+	}
+
+	tests := []struct {
+		name        string
+		response    []string
+		wantScore   float64
+		wantQuality models.MemQuality
+	}{
+		{"all fields", []string{"1789", "bastille", "France"}, 1, models.MemQualityError},
+		{"near-miss field", []string{"1789.0", "Bastile", "France"}, 2.5 / 3, models.MemQualityError},
+		{"two fields", []string{"1789", "Bastille", "Spain"}, 2.0 / 3, models.MemQualityErrorHints},
+		{"one field", []string{"1790", "Versailles", "France"}, 1.0 / 3, models.MemQualityErrorMCQ},
+		{"missing fields", []string{"1790"}, 0, models.MemQualityBlackout},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fields, score := core.ValidateFields(test.response, &card, nil)
+			if len(fields) != 3 {
+				t.Fatalf("ValidateFields() returned %d fields, want 3", len(fields))
+			}
+			if score < test.wantScore-1e-9 || score > test.wantScore+1e-9 {
+				t.Errorf("ValidateFields() score = %f, want %f", score, test.wantScore)
+			}
+
+			validation := new(models.CardResponseValidation)
+			validation.SetFields(fields, score)
+
+			mem := &models.Mem{LearningStage: models.StageToLearn}
+			switch {
+			case validation.Almost:
+				mem.ComputeQualityAlmost()
+			case validation.Validate:
+				mem.ComputeQualitySuccess(&models.Deck{})
+			case validation.Partial:
+				mem.ComputeQualityPartial(validation.Score)
+			default:
+				mem.ComputeQualityFail(&models.Deck{})
+			}
+			if mem.Quality != test.wantQuality {
+				t.Errorf("Quality = %d, want %d", mem.Quality, test.wantQuality)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/memnix/memnixrest/app/models"
+)
+
+func TestAddLapse(t *testing.T) {
+	tests := []struct {
+		name          string
+		lapses        uint
+		leech         bool
+		deck          models.Deck
+		wantLeech     bool
+		wantSuspended bool
+		wantNew       bool
+	}{
+		{"below threshold", 2, false, models.Deck{LeechThreshold: 8}, false, false, false},
+		{"reaches threshold", 7, false, models.Deck{LeechThreshold: 8}, true, false, true},
+		{"reaches threshold and suspends", 7, false, models.Deck{LeechThreshold: 8, LeechSuspend: true}, true, true, true},
+		{"already a leech", 9, true, models.Deck{LeechThreshold: 8, LeechSuspend: true}, true, false, false},
+		{"detection disabled", 20, false, models.Deck{LeechThreshold: 0}, false, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			memDate := &models.MemDate{Lapses: test.lapses, Leech: test.leech}
+
+			if got := memDate.AddLapse(&test.deck); got != test.wantNew {
+				t.Errorf("AddLapse() = %t, want %t", got, test.wantNew)
+			}
+			if memDate.Lapses != test.lapses+1 {
+				t.Errorf("Lapses = %d, want %d", memDate.Lapses, test.lapses+1)
+			}
+			if memDate.Leech != test.wantLeech || memDate.Suspended != test.wantSuspended {
+				t.Errorf("Leech, Suspended = %t, %t, want %t, %t", memDate.Leech, memDate.Suspended, test.wantLeech, test.wantSuspended)
+			}
+		})
+	}
+}
+
+func TestRemoveLapse(t *testing.T) {
+	tests := []struct {
+		name          string
+		lapses        uint
+		deck          models.Deck
+		leeched       bool
+		wantLapses    uint
+		wantLeech     bool
+		wantSuspended bool
+	}{
+		{"lapse below threshold", 3, models.Deck{LeechThreshold: 8, LeechSuspend: true}, false, 2, true, true},
+		{"lapse which made a leech", 8, models.Deck{LeechThreshold: 8}, true, 7, false, true},
+		{"lapse which made a suspended leech", 8, models.Deck{LeechThreshold: 8, LeechSuspend: true}, true, 7, false, false},
+		{"no lapse counted", 0, models.Deck{LeechThreshold: 8}, false, 0, true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			memDate := &models.MemDate{Lapses: test.lapses, Leech: true, Suspended: true}
+
+			memDate.RemoveLapse(&test.deck, test.leeched)
+
+			if memDate.Lapses != test.wantLapses {
+				t.Errorf("Lapses = %d, want %d", memDate.Lapses, test.wantLapses)
+			}
+			if memDate.Leech != test.wantLeech || memDate.Suspended != test.wantSuspended {
+				t.Errorf("Leech, Suspended = %t, %t, want %t, %t", memDate.Leech, memDate.Suspended, test.wantLeech, test.wantSuspended)
+			}
+		})
+	}
+}
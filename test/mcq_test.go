@@ -0,0 +1,66 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/memnix/memnixrest/app/models"
+)
+
+func TestMcqPolicy(t *testing.T) {
+	progressive := models.Deck{McqRepetitions: 2}
+	trivia := models.Deck{McqRepetitions: 5, McqSuccess: models.MemQualityGoodMCQ}
+
+	tests := []struct {
+		name        string
+		deck        models.Deck
+		mem         models.Mem
+		wantMCQ     bool
+		wantSuccess models.MemQuality
+	}{
+		{"progressive learning", progressive, models.Mem{LearningStage: models.StageLearning, Efactor: 2.5}, true, models.MemQualityError},
+		{"progressive hard card", progressive, models.Mem{LearningStage: models.StageReviewing, Efactor: 1.6, Repetition: 8}, true, models.MemQualityError},
+		{"progressive one more review", progressive, models.Mem{LearningStage: models.StageReviewing, Efactor: 2.2, Repetition: 2}, true, models.MemQualityError},
+		{"progressive typed", progressive, models.Mem{LearningStage: models.StageReviewing, Efactor: 2.5, Repetition: 2}, false, models.MemQualityGoodMCQ},
+		{"trivia stays mcq", trivia, models.Mem{LearningStage: models.StageReviewing, Efactor: 2.5, Repetition: 3}, true, models.MemQualityGoodMCQ},
+		{"always mcq", models.Deck{McqPolicy: models.McqPolicyAlways}, models.Mem{LearningStage: models.StageKnown, Efactor: 2.5, Repetition: 9}, true, models.MemQualityError},
+		{"always typed", models.Deck{McqPolicy: models.McqPolicyTyped}, models.Mem{LearningStage: models.StageLearning, Efactor: 1.3}, false, models.MemQualityGoodMCQ},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.mem.IsMCQ(&test.deck); got != test.wantMCQ {
+				t.Errorf("IsMCQ() = %t, want %t", got, test.wantMCQ)
+			}
+
+			test.mem.ComputeQualitySuccess(&test.deck)
+			if test.mem.Quality != test.wantSuccess {
+				t.Errorf("ComputeQualitySuccess() = %d, want %d", test.mem.Quality, test.wantSuccess)
+			}
+		})
+	}
+}
+
+func TestMcqPolicyValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		deck models.Deck
+		want bool
+	}{
+		{"defaults", models.Deck{}, true},
+		{"custom", models.Deck{McqPolicy: models.McqPolicyProgressive, McqRepetitions: 4, McqEfactor: 2.4, McqHardEfactor: 2.0, McqSuccess: models.MemQualityGoodMCQ, McqFail: models.MemQualityBlackout}, true},
+		{"unknown policy", models.Deck{McqPolicy: 3}, false},
+		{"hard above soft efactor", models.Deck{McqEfactor: 1.8, McqHardEfactor: 2.0}, false},
+		{"failing success quality", models.Deck{McqSuccess: models.MemQualityErrorHints}, false},
+		{"passing fail quality", models.Deck{McqFail: models.MemQualityError}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deck := test.deck
+			deck.DeckName, deck.Description = "Capitals", "Capitals of the world"
+			if got := !deck.NotValidate(); got != test.want {
+				t.Errorf("valid = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
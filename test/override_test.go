@@ -0,0 +1,63 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+)
+
+func TestIsOverridable(t *testing.T) {
+	review := models.Mem{PreviousDate: time.Now()}
+
+	tests := []struct {
+		name string
+		last models.Mem
+		next models.Mem
+		want bool
+	}{
+		{"rejected response", models.Mem{Quality: models.MemQualityErrorHints, Response: "Tchaikovsky"}, review, true},
+		{"accepted response", models.Mem{Quality: models.MemQualityPerfect, Response: "Tchaikovski"}, review, false},
+		{"self evaluation", models.Mem{Quality: models.MemQualityBlackout}, review, false},
+		{"review before undo support", models.Mem{Quality: models.MemQualityErrorHints, Response: "Tchaikovsky"}, models.Mem{}, false},
+		{"card reset", models.Mem{Quality: models.MemQualityErrorHints, Response: "Tchaikovsky"}, models.Mem{Reset: true}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.last.IsOverridable(&test.next); got != test.want {
+				t.Errorf("IsOverridable() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOverrideLeech(t *testing.T) {
+	deck := models.Deck{LeechThreshold: 8, LeechSuspend: true}
+	previous := time.Now().AddDate(0, 0, -1)
+
+	memDate := &models.MemDate{Deck: deck, NextDate: previous, LearningStage: models.StageReviewing, Lapses: 7}
+
+	// The rejected response makes the card a suspended leech
+	last := &models.Mem{Quality: models.MemQualityErrorHints, LearningStage: models.StageReviewing, Response: "Tchaikovsky"}
+	next := &models.Mem{}
+	next.SetPrevious(memDate)
+	if !last.IsLapse() {
+		t.Fatalf("IsLapse() = false, want true")
+	}
+	last.Leeched = memDate.AddLapse(&memDate.Deck)
+	memDate.LearningStage = models.StageToRelearn
+	if !memDate.Leech || !memDate.Suspended {
+		t.Fatalf("Leech, Suspended = %t, %t, want true, true", memDate.Leech, memDate.Suspended)
+	}
+
+	// Overriding it restores the card before the response is graded again
+	memDate.Restore(next, last)
+
+	if memDate.Lapses != 7 || memDate.Leech || memDate.Suspended {
+		t.Errorf("Lapses, Leech, Suspended = %d, %t, %t, want 7, false, false", memDate.Lapses, memDate.Leech, memDate.Suspended)
+	}
+	if !memDate.NextDate.Equal(previous) || memDate.LearningStage != models.StageReviewing {
+		t.Errorf("NextDate, LearningStage = %v, %d, want %v, %d", memDate.NextDate, memDate.LearningStage, previous, models.StageReviewing)
+	}
+}
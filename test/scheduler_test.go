@@ -0,0 +1,408 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/core"
+)
+
+func TestGetScheduler(t *testing.T) {
+	tests := []struct {
+		name          string
+		schedulerType models.SchedulerType
+		want          core.Scheduler
+	}{
+		{
+			name:          "sm2",
+			schedulerType: models.SchedulerSM2,
+			want:          core.GetScheduler(models.SchedulerSM2),
+		},
+		{
+			name:          "unknown falls back to sm2",
+			schedulerType: models.SchedulerType(42),
+			want:          core.GetScheduler(models.SchedulerSM2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := core.GetScheduler(tt.schedulerType); got != tt.want {
+				t.Errorf("GetScheduler() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulerReview(t *testing.T) {
+	tests := []struct {
+		name           string
+		schedulerType  models.SchedulerType
+		validation     bool
+		wantInterval   uint
+		wantRepetition uint
+		wantStage      models.LearningStage
+	}{
+		{
+			name:           "sm2 success",
+			schedulerType:  models.SchedulerSM2,
+			validation:     true,
+			wantInterval:   1,
+			wantRepetition: 1,
+			wantStage:      models.StageLearning,
+		},
+		{
+			name:           "sm2 fail",
+			schedulerType:  models.SchedulerSM2,
+			validation:     false,
+			wantInterval:   0,
+			wantRepetition: 0,
+			wantStage:      models.StageToLearn,
+		},
+		{
+			name:           "fsrs success",
+			schedulerType:  models.SchedulerFSRS,
+			validation:     true,
+			wantInterval:   2,
+			wantRepetition: 1,
+			wantStage:      models.StageLearning,
+		},
+		{
+			name:           "fsrs fail",
+			schedulerType:  models.SchedulerFSRS,
+			validation:     false,
+			wantInterval:   0,
+			wantRepetition: 0,
+			wantStage:      models.StageToLearn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			last, mem := new(models.Mem), new(models.Mem)
+			last.FillDefaultValues(1, 1, 0)
+			last.Quality = models.MemQualityGoodMCQ
+
+			core.GetScheduler(tt.schedulerType).Review(last, mem, tt.validation)
+
+			if mem.Interval != tt.wantInterval || mem.Repetition != tt.wantRepetition || mem.LearningStage != tt.wantStage {
+				t.Errorf("Review() = (%d, %d, %d), want (%d, %d, %d)", mem.Interval, mem.Repetition, mem.LearningStage,
+					tt.wantInterval, tt.wantRepetition, tt.wantStage)
+			}
+		})
+	}
+}
+
+func TestFSRSStability(t *testing.T) {
+	scheduler := core.NewFSRSScheduler()
+
+	last, mem := new(models.Mem), new(models.Mem)
+	last.FillDefaultValues(1, 1, 0)
+	last.Quality = models.MemQualityGoodMCQ
+	last.Stability, last.Difficulty = 10, 5
+	last.CreatedAt = time.Now().AddDate(0, 0, -10)
+
+	scheduler.Review(last, mem, true)
+	if mem.Stability <= last.Stability {
+		t.Errorf("Review() success stability = %f, want > %f", mem.Stability, last.Stability)
+	}
+
+	scheduler.Review(last, mem, false)
+	if mem.Stability >= last.Stability {
+		t.Errorf("Review() fail stability = %f, want < %f", mem.Stability, last.Stability)
+	}
+}
+
+func TestComputeLearningStep(t *testing.T) {
+	deck := &models.Deck{LearnSteps: "1m 10m", RelearnSteps: "10m"}
+
+	tests := []struct {
+		name       string
+		lastStage  models.LearningStage
+		lastStep   uint
+		validation bool
+		wantStep   time.Duration
+		wantInStep bool
+		wantStage  models.LearningStage
+	}{
+		{
+			name:       "new card success goes to next step",
+			lastStage:  models.StageToLearn,
+			lastStep:   0,
+			validation: true,
+			wantStep:   10 * time.Minute,
+			wantInStep: true,
+			wantStage:  models.StageToLearn,
+		},
+		{
+			name:       "new card graduates after last step",
+			lastStage:  models.StageToLearn,
+			lastStep:   1,
+			validation: true,
+			wantInStep: false,
+			wantStage:  models.StageLearning,
+		},
+		{
+			name:       "new card fail restarts steps",
+			lastStage:  models.StageToLearn,
+			lastStep:   1,
+			validation: false,
+			wantStep:   time.Minute,
+			wantInStep: true,
+			wantStage:  models.StageToLearn,
+		},
+		{
+			name:       "lapse goes to relearning",
+			lastStage:  models.StageReviewing,
+			validation: false,
+			wantStep:   10 * time.Minute,
+			wantInStep: true,
+			wantStage:  models.StageToRelearn,
+		},
+		{
+			name:       "review success has no step",
+			lastStage:  models.StageReviewing,
+			validation: true,
+			wantInStep: false,
+			wantStage:  models.StageLearning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			last, mem := new(models.Mem), new(models.Mem)
+			last.FillDefaultValues(1, 1, 0)
+			last.LearningStage, last.Step = tt.lastStage, tt.lastStep
+			mem.LearningStage = models.StageLearning
+
+			step, inStep := core.ComputeLearningStep(last, mem, tt.validation, deck)
+			if step != tt.wantStep || inStep != tt.wantInStep || mem.LearningStage != tt.wantStage {
+				t.Errorf("ComputeLearningStep() = (%v, %v, %d), want (%v, %v, %d)", step, inStep, mem.LearningStage,
+					tt.wantStep, tt.wantInStep, tt.wantStage)
+			}
+		})
+	}
+}
+
+func TestFuzzInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval uint
+		wantMin  uint
+		wantMax  uint
+	}{
+		{name: "no fuzz for one day", interval: 1, wantMin: 1, wantMax: 1},
+		{name: "short interval", interval: 6, wantMin: 5, wantMax: 7},
+		{name: "medium interval", interval: 20, wantMin: 17, wantMax: 23},
+		{name: "long interval", interval: 100, wantMin: 95, wantMax: 105},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotMin, gotMax := core.FuzzRange(tt.interval); gotMin != tt.wantMin || gotMax != tt.wantMax {
+				t.Errorf("FuzzRange() = (%d, %d), want (%d, %d)", gotMin, gotMax, tt.wantMin, tt.wantMax)
+			}
+
+			spread := make(map[uint]bool)
+			for cardID := uint(1); cardID <= 50; cardID++ {
+				mem := &models.Mem{UserID: 1, CardID: cardID, Repetition: 4}
+				got := core.FuzzInterval(tt.interval, mem)
+				if got < tt.wantMin || got > tt.wantMax {
+					t.Errorf("FuzzInterval() = %d, want in [%d, %d]", got, tt.wantMin, tt.wantMax)
+				}
+				if got != core.FuzzInterval(tt.interval, mem) {
+					t.Errorf("FuzzInterval() is not deterministic")
+				}
+				spread[got] = true
+			}
+
+			if tt.wantMin != tt.wantMax && len(spread) < 2 {
+				t.Errorf("FuzzInterval() doesn't spread cards")
+			}
+
+			clozes := make(map[uint]bool)
+			for cloze := uint(1); cloze <= 50; cloze++ {
+				clozes[core.FuzzInterval(tt.interval, &models.Mem{UserID: 1, CardID: 1, Cloze: cloze, Repetition: 4})] = true
+			}
+
+			if tt.wantMin != tt.wantMax && len(clozes) < 2 {
+				t.Errorf("FuzzInterval() doesn't spread the clozes of a card")
+			}
+		})
+	}
+}
+
+func TestPreviewSelfEvaluation(t *testing.T) {
+	tests := []struct {
+		name      string
+		scheduler models.SchedulerType
+		steps     string
+		wantStep  bool // Again goes back to a relearning step
+	}{
+		{name: "sm2 without steps", scheduler: models.SchedulerSM2},
+		{name: "sm2 with relearning step", scheduler: models.SchedulerSM2, steps: "10m", wantStep: true},
+		{name: "fsrs without steps", scheduler: models.SchedulerFSRS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			last := &models.Mem{UserID: 1, CardID: 1, Repetition: 5, Efactor: 2.5, Interval: 10, LearningStage: models.StageReviewing,
+				Stability: 10, Difficulty: 5, Quality: models.MemQualityNone}
+			last.CreatedAt = time.Now().AddDate(0, 0, -10)
+			memDate := &models.MemDate{Deck: models.Deck{Scheduler: tt.scheduler, RelearnSteps: tt.steps}}
+
+			previews := core.PreviewSelfEvaluation(last, memDate, time.Time{})
+			if len(previews) != 4 {
+				t.Fatalf("PreviewSelfEvaluation() returned %d previews, want 4", len(previews))
+			}
+
+			if again := previews[0]; again.Interval != 0 || (tt.wantStep && again.NextDate.After(time.Now().Add(11*time.Minute))) {
+				t.Errorf("Again preview = %+v, want a review within the day", again)
+			}
+			for i := 2; i < len(previews); i++ {
+				if previews[i].Interval <= previews[i-1].Interval || !previews[i].NextDate.After(previews[i-1].NextDate) {
+					t.Errorf("preview %d = %+v, want later than %+v", i, previews[i], previews[i-1])
+				}
+			}
+
+			if last.Quality != models.MemQualityNone || memDate.NextDate != (time.Time{}) {
+				t.Errorf("PreviewSelfEvaluation() changed its arguments")
+			}
+		})
+	}
+}
+
+func TestComputeQualitySelfEvaluated(t *testing.T) {
+	tests := []struct {
+		name        string
+		grade       uint
+		wantSuccess bool
+	}{
+		{"again", 1, false},
+		{"hard", 2, true},
+		{"good", 3, true},
+		{"easy", 4, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := &models.Mem{LearningStage: models.StageReviewing}
+			mem.ComputeQualitySelfEvaluated(tt.grade)
+
+			if mem.IsSuccess() != tt.wantSuccess || mem.IsLapse() == tt.wantSuccess {
+				t.Errorf("IsSuccess(), IsLapse() = %t, %t, want %t, %t", mem.IsSuccess(), mem.IsLapse(), tt.wantSuccess, !tt.wantSuccess)
+			}
+		})
+	}
+}
+
+func TestCramInterval(t *testing.T) {
+	user := &models.User{Timezone: "UTC", DayStart: 4}
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	exam := func(days int) time.Time {
+		return time.Date(2022, 6, 1+days, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		interval uint
+		examDate time.Time
+		want     uint
+	}{
+		{"no exam date", 30, time.Time{}, 30},
+		{"interval before the exam", 3, exam(10), 3},
+		{"interval past the exam", 30, exam(10), 9},
+		{"interval on the exam day", 10, exam(10), 9},
+		{"exam tomorrow", 6, exam(1), 1},
+		{"exam today", 6, exam(0), 6},
+		{"exam passed", 30, exam(-3), 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := core.CramInterval(tt.interval, user, tt.examDate, now); got != tt.want {
+				t.Errorf("CramInterval(%d) = %d, want %d", tt.interval, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExamDayStart(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	user := &models.User{Timezone: "Europe/Paris", DayStart: 4}
+	want := time.Date(2022, 6, 11, 4, 0, 0, 0, paris)
+
+	tests := []struct {
+		name     string
+		examDate time.Time
+	}{
+		{"in the user timezone", time.Date(2022, 6, 11, 0, 0, 0, 0, paris)},
+		{"returned in UTC", time.Date(2022, 6, 10, 22, 0, 0, 0, time.UTC)},
+		{"late on the exam day", time.Date(2022, 6, 11, 21, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := core.ExamDayStart(user, tt.examDate); !got.Equal(want) {
+				t.Errorf("ExamDayStart() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCramNextDate(t *testing.T) {
+	user := &models.User{Timezone: "UTC", DayStart: 4}
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := func(days int) time.Time {
+		return time.Date(2022, 6, 1+days, 4, 0, 0, 0, time.UTC)
+	}
+	exam := time.Date(2022, 6, 11, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		nextDate time.Time
+		examDate time.Time
+		want     time.Time
+	}{
+		{"no exam date", day(30), time.Time{}, day(30)},
+		{"due before the exam", day(5), exam, day(5)},
+		{"due the day before the exam", day(9), exam, day(9)},
+		{"due on the exam day", day(10), exam, day(9)},
+		{"due weeks after the exam", day(40), exam, day(9)},
+		{"in a learning step", now.Add(10 * time.Minute), exam, now.Add(10 * time.Minute)},
+		{"exam passed", day(40), time.Date(2022, 5, 20, 0, 0, 0, 0, time.UTC), day(40)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := core.CramNextDate(tt.nextDate, user, tt.examDate, now); !got.Equal(tt.want) {
+				t.Errorf("CramNextDate(%v) = %v, want %v", tt.nextDate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExamDateValidation(t *testing.T) {
+	dayStart := time.Date(2022, 6, 1, 4, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		examDate time.Time
+		want     bool
+	}{
+		{"no exam date", time.Time{}, false},
+		{"today", time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), false},
+		{"future", time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC), false},
+		{"past", time.Date(2022, 5, 31, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.ExamDateConfig{ExamDate: tt.examDate}
+			if got := config.NotValidate(dayStart); got != tt.want {
+				t.Errorf("NotValidate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+	"github.com/memnix/memnixrest/pkg/utils"
+)
+
+func TestSessionAnswer(t *testing.T) {
+	start := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+	memDates := make([]models.MemDate, 5)
+	for i := range memDates {
+		memDates[i].ID = uint(i + 1)
+	}
+
+	tests := []struct {
+		name      string
+		answers   []uint // MemDate IDs answered in order
+		failed    map[uint]bool
+		wantQueue []uint
+		wantDone  bool
+	}{
+		{"passed", []uint{1}, nil, []uint{2, 3, 4, 5}, false},
+		{"failed card is requeued", []uint{1}, map[uint]bool{1: true}, []uint{2, 3, 4, 1, 5}, false},
+		{"out of order answer", []uint{4}, nil, []uint{1, 2, 3, 5}, false},
+		{"card outside the queue", []uint{9}, nil, []uint{1, 2, 3, 4, 5}, false},
+		{"every card passed", []uint{1, 2, 3, 4, 5}, nil, []uint{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session := new(models.Session)
+			session.Start(1, 1, false, memDates, start)
+
+			now := start
+			for _, id := range test.answers {
+				now = now.Add(10 * time.Second)
+				session.Answer(id, !test.failed[id], now)
+			}
+
+			if got := session.GetQueue(); !reflect.DeepEqual(got, test.wantQueue) {
+				t.Errorf("GetQueue() = %v, want %v", got, test.wantQueue)
+			}
+			if done := session.Status == models.SessionFinished; done != test.wantDone {
+				t.Errorf("finished = %t, want %t", done, test.wantDone)
+			}
+		})
+	}
+}
+
+func TestSessionSummary(t *testing.T) {
+	start := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+	memDates := []models.MemDate{{}, {}}
+	memDates[0].ID, memDates[1].ID = 1, 2
+
+	session := new(models.Session)
+	session.Start(1, 0, false, memDates, start)
+
+	session.Answer(1, false, start.Add(20*time.Second))
+	session.Answer(2, true, start.Add(40*time.Second))
+	session.Answer(1, true, start.Add(time.Hour)) // Idle gap is capped
+
+	summary := session.GetSummary()
+	wantElapsed := uint((40*time.Second + utils.MaxSessionIdle).Seconds())
+
+	if !summary.Finished || summary.Total != 2 || summary.Reviewed != 3 || summary.Passed != 2 || summary.Failed != 1 || summary.Requeued != 1 {
+		t.Errorf("GetSummary() = %+v", summary)
+	}
+	if summary.Elapsed != wantElapsed {
+		t.Errorf("Elapsed = %d, want %d", summary.Elapsed, wantElapsed)
+	}
+	if summary.Accuracy < 0.66 || summary.Accuracy > 0.67 {
+		t.Errorf("Accuracy = %f, want 2/3", summary.Accuracy)
+	}
+}
+
+func TestCardSelfResponseIsSuccess(t *testing.T) {
+	tests := []struct {
+		grade uint
+		want  bool
+	}{
+		{1, false},
+		{2, true},
+		{3, true},
+		{4, true},
+	}
+
+	for _, test := range tests {
+		response := models.CardSelfResponse{Quality: test.grade}
+		if got := response.IsSuccess(); got != test.want {
+			t.Errorf("IsSuccess() with grade %d = %t, want %t", test.grade, got, test.want)
+		}
+	}
+}
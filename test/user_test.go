@@ -0,0 +1,44 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/memnix/memnixrest/app/models"
+)
+
+func TestGetDayStart(t *testing.T) {
+	tests := []struct {
+		name string
+		user models.User
+		t    time.Time
+		want time.Time
+	}{
+		{
+			name: "late evening is still today",
+			user: models.User{Timezone: "UTC", DayStart: 4},
+			t:    time.Date(2022, 9, 10, 23, 0, 0, 0, time.UTC),
+			want: time.Date(2022, 9, 10, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "before day start is yesterday",
+			user: models.User{Timezone: "UTC", DayStart: 4},
+			t:    time.Date(2022, 9, 10, 2, 0, 0, 0, time.UTC),
+			want: time.Date(2022, 9, 9, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "user timezone",
+			user: models.User{Timezone: "America/New_York", DayStart: 0},
+			t:    time.Date(2022, 9, 10, 2, 0, 0, 0, time.UTC),
+			want: time.Date(2022, 9, 9, 4, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.GetDayStart(tt.t); !got.Equal(tt.want) {
+				t.Errorf("GetDayStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}